@@ -0,0 +1,476 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// generatedRoutesFile is written next to the controllers by generate:routes.
+const generatedRoutesFile = "zz_forge_routes.go"
+
+const forgeImport = "github.com/BisiOlaYemi/forge/pkg/forge"
+
+// annotatedController collects the @route annotations of one controller.
+// Unannotated lists its Handle* methods without annotations, which keep the
+// routes derived from their names.
+type annotatedController struct {
+	Name        string
+	Receiver    string
+	Routes      []annotatedRoute
+	Unannotated []string
+}
+
+type annotatedRoute struct {
+	Handler     string
+	Method      string
+	Path        string
+	Description string
+	Params      []annotatedParam
+	Body        string
+	Response    string
+	Responses   []annotatedResponse
+}
+
+type annotatedParam struct {
+	Name        string
+	In          string
+	Type        string
+	Required    bool
+	Description string
+}
+
+type annotatedResponse struct {
+	Status int
+	Value  string
+}
+
+// routeFile holds what the annotations of a package need to generate.
+type routeFile struct {
+	Package     string
+	StdImports  []string
+	Imports     []string
+	Controllers []annotatedController
+}
+
+// openAPITypes maps @param types to OpenAPI types.
+var openAPITypes = map[string]string{
+	"string": "string", "int": "integer", "integer": "integer", "number": "number",
+	"float": "number", "bool": "boolean", "boolean": "boolean",
+}
+
+// builtinValues maps the keywords usable in @body and @response to values.
+var builtinValues = map[string]string{
+	"object": "forge.H{}", "string": `""`, "int": "0", "integer": "0",
+	"number": "float64(0)", "float": "float64(0)", "bool": "false", "boolean": "false",
+}
+
+// builtinTypes maps the same keywords to Go types for slices such as []int.
+var builtinTypes = map[string]string{
+	"object": "forge.H", "string": "string", "int": "int", "integer": "int",
+	"number": "float64", "float": "float64", "bool": "bool", "boolean": "bool",
+}
+
+// generateRoutes parses the controllers in dir and writes their @route
+// annotations to zz_forge_routes.go as Routes() methods. It returns the
+// number of routes written. Routes() only overrides the annotated handlers:
+// the controller's other Handle* methods are still routed by convention.
+func generateRoutes(dir string) (int, error) {
+	file, err := parseRouteAnnotations(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	target := filepath.Join(dir, generatedRoutesFile)
+	count := 0
+	for _, c := range file.Controllers {
+		count += len(c.Routes)
+	}
+	if count == 0 {
+		if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+			return 0, err
+		}
+		return 0, nil
+	}
+
+	source, err := renderRoutes(file)
+	if err != nil {
+		return 0, err
+	}
+	return count, os.WriteFile(target, source, 0644)
+}
+
+// parseRouteAnnotations reads the doc comments of the Handle* methods in dir.
+// Annotations referencing unknown types are reported with their position.
+func parseRouteAnnotations(dir string) (*routeFile, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go") && info.Name() != generatedRoutesFile
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected one package in %s, found %d", dir, len(pkgs))
+	}
+
+	var pkg *ast.Package
+	for _, p := range pkgs {
+		pkg = p
+	}
+	result := &routeFile{Package: pkg.Name}
+
+	// Local types and the receivers that already define Routes().
+	types := make(map[string]ast.Expr)
+	definesRoutes := make(map[string]bool)
+	for _, f := range pkg.Files {
+		for _, decl := range f.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					if spec, ok := spec.(*ast.TypeSpec); ok {
+						types[spec.Name.Name] = spec.Type
+					}
+				}
+			case *ast.FuncDecl:
+				if decl.Recv != nil && decl.Name.Name == "Routes" {
+					name, _ := receiverName(decl)
+					definesRoutes[name] = true
+				}
+			}
+		}
+	}
+
+	controllers := make(map[string]*annotatedController)
+	unannotated := make(map[string][]string)
+	imports := map[string]bool{forgeImport: true}
+	resolver := &typeResolver{types: types, imports: imports}
+
+	fileNames := make([]string, 0, len(pkg.Files))
+	for name := range pkg.Files {
+		fileNames = append(fileNames, name)
+	}
+	sort.Strings(fileNames)
+
+	for _, name := range fileNames {
+		f := pkg.Files[name]
+		resolver.file = f
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil {
+				continue
+			}
+
+			var routes []annotatedRoute
+			if fn.Doc != nil {
+				if routes, err = parseHandlerDoc(fn, resolver); err != nil {
+					return nil, fmt.Errorf("%s: %w", fset.Position(fn.Pos()), err)
+				}
+			}
+			recv, pointer := receiverName(fn)
+			if len(routes) == 0 {
+				if strings.HasPrefix(fn.Name.Name, "Handle") {
+					unannotated[recv] = append(unannotated[recv], fn.Name.Name)
+				}
+				continue
+			}
+
+			if definesRoutes[recv] {
+				return nil, fmt.Errorf("%s: %s has @route annotations but already defines Routes()", fset.Position(fn.Pos()), recv)
+			}
+			controller, ok := controllers[recv]
+			if !ok {
+				controller = &annotatedController{Name: recv, Receiver: recv}
+				controllers[recv] = controller
+			}
+			if pointer {
+				controller.Receiver = "*" + recv
+			}
+			controller.Routes = append(controller.Routes, routes...)
+		}
+	}
+
+	for _, c := range controllers {
+		c.Unannotated = unannotated[c.Name]
+		sort.Strings(c.Unannotated)
+		result.Controllers = append(result.Controllers, *c)
+	}
+	sort.Slice(result.Controllers, func(i, j int) bool {
+		return result.Controllers[i].Name < result.Controllers[j].Name
+	})
+	for path := range imports {
+		if strings.Contains(strings.Split(path, "/")[0], ".") {
+			result.Imports = append(result.Imports, path)
+		} else {
+			result.StdImports = append(result.StdImports, path)
+		}
+	}
+	sort.Strings(result.StdImports)
+	sort.Strings(result.Imports)
+	return result, nil
+}
+
+// parseHandlerDoc returns one route per @route line of fn's doc comment,
+// each carrying the method's other annotations.
+func parseHandlerDoc(fn *ast.FuncDecl, resolver *typeResolver) ([]annotatedRoute, error) {
+	var routes []annotatedRoute
+	var shared annotatedRoute
+
+	for _, line := range strings.Split(fn.Doc.Text(), "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "@") {
+			continue
+		}
+		tag, value, _ := strings.Cut(line, " ")
+		value = strings.TrimSpace(value)
+
+		switch tag {
+		case "@route":
+			fields := strings.Fields(value)
+			if len(fields) != 2 || !strings.HasPrefix(fields[1], "/") {
+				return nil, fmt.Errorf("invalid @route %q, expected METHOD /path", value)
+			}
+			routes = append(routes, annotatedRoute{Method: strings.ToUpper(fields[0]), Path: fields[1]})
+		case "@desc":
+			shared.Description = value
+		case "@body":
+			body, err := resolver.value(value)
+			if err != nil {
+				return nil, fmt.Errorf("@body: %w", err)
+			}
+			shared.Body = body
+		case "@param":
+			param, err := parseParam(value)
+			if err != nil {
+				return nil, err
+			}
+			shared.Params = append(shared.Params, param)
+		case "@response":
+			statusText, typeName, _ := strings.Cut(value, " ")
+			status, err := strconv.Atoi(statusText)
+			if err != nil || status < 100 || status > 599 {
+				return nil, fmt.Errorf("invalid @response status %q", statusText)
+			}
+			response := "nil"
+			if typeName = strings.TrimSpace(typeName); typeName != "" {
+				if response, err = resolver.value(typeName); err != nil {
+					return nil, fmt.Errorf("@response: %w", err)
+				}
+			}
+			if status == 200 {
+				shared.Response = response
+			} else {
+				shared.Responses = append(shared.Responses, annotatedResponse{Status: status, Value: response})
+			}
+		}
+	}
+
+	if len(routes) == 0 {
+		return nil, nil
+	}
+	if !strings.HasPrefix(fn.Name.Name, "Handle") {
+		return nil, fmt.Errorf("@route on %s, only Handle* methods are routed", fn.Name.Name)
+	}
+
+	for i := range routes {
+		for _, param := range shared.Params {
+			if param.In == "path" && !hasPathParam(routes[i].Path, param.Name) {
+				return nil, fmt.Errorf("@param %s is not a parameter of %s", param.Name, routes[i].Path)
+			}
+		}
+		method, path := routes[i].Method, routes[i].Path
+		routes[i] = shared
+		routes[i].Handler = fn.Name.Name
+		routes[i].Method = method
+		routes[i].Path = path
+	}
+	return routes, nil
+}
+
+// parseParam parses `name in type required "description"`.
+func parseParam(value string) (annotatedParam, error) {
+	fields := strings.Fields(value)
+	if len(fields) < 4 {
+		return annotatedParam{}, fmt.Errorf("invalid @param %q, expected name in type required \"description\"", value)
+	}
+
+	param := annotatedParam{Name: fields[0], In: fields[1]}
+	switch param.In {
+	case "path", "query", "header":
+	default:
+		return param, fmt.Errorf("invalid @param location %q for %s", param.In, param.Name)
+	}
+	typ, ok := openAPITypes[fields[2]]
+	if !ok {
+		return param, fmt.Errorf("@param %s references unknown type %q", param.Name, fields[2])
+	}
+	param.Type = typ
+	required, err := strconv.ParseBool(fields[3])
+	if err != nil {
+		return param, fmt.Errorf("invalid @param required flag %q for %s", fields[3], param.Name)
+	}
+	param.Required = required
+
+	if len(fields) > 4 {
+		description := strings.Join(fields[4:], " ")
+		if unquoted, err := strconv.Unquote(description); err == nil {
+			description = unquoted
+		}
+		param.Description = description
+	}
+	return param, nil
+}
+
+func hasPathParam(path, name string) bool {
+	for _, segment := range strings.Split(path, "/") {
+		if segment == ":"+name || segment == ":"+name+"?" {
+			return true
+		}
+	}
+	return false
+}
+
+func receiverName(fn *ast.FuncDecl) (string, bool) {
+	expr := fn.Recv.List[0].Type
+	star, pointer := expr.(*ast.StarExpr)
+	if pointer {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name, pointer
+	}
+	return "", pointer
+}
+
+// typeResolver turns type names from annotations into Go values for the
+// generated file, failing on types the package can't see.
+type typeResolver struct {
+	types   map[string]ast.Expr
+	imports map[string]bool
+	file    *ast.File
+}
+
+func (r *typeResolver) value(name string) (string, error) {
+	name = strings.TrimPrefix(name, "*")
+	if v, ok := builtinValues[name]; ok {
+		return v, nil
+	}
+	if elem, ok := strings.CutPrefix(name, "[]"); ok {
+		typ, err := r.typeName(elem)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + typ + "{}", nil
+	}
+
+	typ, err := r.typeName(name)
+	if err != nil {
+		return "", err
+	}
+	switch r.types[name].(type) {
+	case *ast.StructType, *ast.ArrayType, *ast.MapType:
+		return typ + "{}", nil
+	}
+	return "*new(" + typ + ")", nil
+}
+
+func (r *typeResolver) typeName(name string) (string, error) {
+	name = strings.TrimPrefix(name, "*")
+	if t, ok := builtinTypes[name]; ok {
+		return t, nil
+	}
+	if _, ok := r.types[name]; ok {
+		return name, nil
+	}
+
+	if pkgName, _, ok := strings.Cut(name, "."); ok && r.file != nil {
+		for _, spec := range r.file.Imports {
+			path, _ := strconv.Unquote(spec.Path.Value)
+			alias := filepath.Base(path)
+			if spec.Name != nil {
+				alias = spec.Name.Name
+			}
+			if alias == pkgName {
+				r.imports[path] = true
+				return name, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("unknown type %q", name)
+}
+
+var routesTemplate = template.Must(template.New("routes").Funcs(template.FuncMap{"join": strings.Join}).Parse(`// Code generated by forge generate:routes. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- range .StdImports}}
+	{{printf "%q" .}}
+{{- end}}
+{{- if .StdImports}}
+{{end}}
+{{- range .Imports}}
+	{{printf "%q" .}}
+{{- end}}
+)
+{{range .Controllers}}
+// Routes returns the routes annotated on {{.Name}}.
+{{- if .Unannotated}}
+// Handlers without annotations are routed by convention: {{join .Unannotated ", "}}.
+{{- end}}
+func (c {{.Receiver}}) Routes() []forge.RouteMetadata {
+	return []forge.RouteMetadata{
+	{{- range .Routes}}
+		{
+			Handler: {{printf "%q" .Handler}},
+			Method: {{printf "%q" .Method}},
+			Path: {{printf "%q" .Path}},
+			{{- if .Description}}
+			Description: {{printf "%q" .Description}},
+			{{- end}}
+			{{- if .Params}}
+			Params: []forge.RouteParam{
+			{{- range .Params}}
+				{Name: {{printf "%q" .Name}}, In: {{printf "%q" .In}}, Type: {{printf "%q" .Type}}, Required: {{.Required}}, Description: {{printf "%q" .Description}}},
+			{{- end}}
+			},
+			{{- end}}
+			{{- if .Body}}
+			RequestBody: {{.Body}},
+			{{- end}}
+			{{- if .Response}}
+			Response: {{.Response}},
+			{{- end}}
+			{{- if .Responses}}
+			Responses: map[int]interface{}{
+			{{- range .Responses}}
+				{{.Status}}: {{.Value}},
+			{{- end}}
+			},
+			{{- end}}
+		},
+	{{- end}}
+	}
+}
+{{end}}`))
+
+func renderRoutes(file *routeFile) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := routesTemplate.Execute(&buf, file); err != nil {
+		return nil, err
+	}
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated routes: %w\n%s", err, buf.String())
+	}
+	return source, nil
+}
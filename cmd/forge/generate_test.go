@@ -0,0 +1,142 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const annotatedSource = `package api
+
+import (
+	"time"
+
+	"github.com/BisiOlaYemi/forge/pkg/forge"
+)
+
+type PostController struct {
+	forge.Controller
+}
+
+type Post struct {
+	Title     string    ` + "`json:\"title\"`" + `
+	CreatedAt time.Time ` + "`json:\"created_at\"`" + `
+}
+
+type CreatePostRequest struct {
+	Title string ` + "`json:\"title\"`" + `
+}
+
+// HandleGetPost returns a post of a user
+// @route GET /users/:userId/posts/:postId
+// @desc Get a post
+// @param userId path int true "User ID"
+// @param postId path int true "Post ID"
+// @param fields query string false "Fields to include"
+// @response 200 Post
+// @response 404
+func (c *PostController) HandleGetPost(ctx *forge.Context) error {
+	return nil
+}
+
+// HandleCreatePost creates a post
+// @route POST /users/:userId/posts
+// @route PUT /users/:userId/posts
+// @body CreatePostRequest
+// @response 201 []Post
+// @response 400 time.Duration
+func (c *PostController) HandleCreatePost(ctx *forge.Context) error {
+	return nil
+}
+
+// HandleGetPosts is routed by convention
+func (c *PostController) HandleGetPosts(ctx *forge.Context) error {
+	return nil
+}
+`
+
+func writeController(t *testing.T, source string) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "post_controller.go"), []byte(source), 0644))
+	return dir
+}
+
+func TestGenerateRoutes(t *testing.T) {
+	dir := writeController(t, annotatedSource)
+
+	count, err := generateRoutes(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	data, err := os.ReadFile(filepath.Join(dir, generatedRoutesFile))
+	require.NoError(t, err)
+	generated := string(data)
+
+	assert.Contains(t, generated, "// Code generated by forge generate:routes. DO NOT EDIT.")
+	assert.Contains(t, generated, "import (\n\t\"time\"\n\n\t\"github.com/BisiOlaYemi/forge/pkg/forge\"\n)")
+	assert.Contains(t, generated, "func (c *PostController) Routes() []forge.RouteMetadata {")
+	assert.Contains(t, generated, `Path:        "/users/:userId/posts/:postId",`)
+	assert.Contains(t, generated, `{Name: "userId", In: "path", Type: "integer", Required: true, Description: "User ID"},`)
+	assert.Contains(t, generated, `{Name: "fields", In: "query", Type: "string", Required: false, Description: "Fields to include"},`)
+	assert.Regexp(t, `Response:\s+Post\{\},`, generated)
+	assert.Contains(t, generated, "404: nil,")
+	assert.Contains(t, generated, `Method:      "PUT",`)
+	assert.Contains(t, generated, "RequestBody: CreatePostRequest{},")
+	assert.Contains(t, generated, "201: []Post{},")
+	assert.Contains(t, generated, "400: *new(time.Duration),")
+	assert.NotRegexp(t, `Handler:\s+"HandleGetPosts"`, generated)
+	assert.Contains(t, generated, "// Handlers without annotations are routed by convention: HandleGetPosts.\nfunc (c *PostController) Routes()")
+}
+
+func TestGenerateRoutesRejectsUnknownTypes(t *testing.T) {
+	tests := map[string]string{
+		"@body CreatePostRequest":                              "@body Missing",
+		"@response 200 Post":                                   "@response 200 Missing",
+		"@response 400 time.Duration":                          "@response 400 json.RawMessage",
+		`@param fields query string false "Fields to include"`: `@param fields query uuid false "Fields"`,
+	}
+	for annotation, broken := range tests {
+		source := replaceOnce(t, annotatedSource, annotation, broken)
+		dir := writeController(t, source)
+
+		_, err := generateRoutes(dir)
+		require.Error(t, err, broken)
+		assert.Contains(t, err.Error(), "unknown type", broken)
+		assert.Contains(t, err.Error(), "post_controller.go:", broken)
+		assert.NoFileExists(t, filepath.Join(dir, generatedRoutesFile))
+	}
+}
+
+func TestGenerateRoutesValidatesAnnotations(t *testing.T) {
+	source := replaceOnce(t, annotatedSource, `@param postId path int true "Post ID"`, `@param id path int true "Post ID"`)
+	_, err := generateRoutes(writeController(t, source))
+	assert.ErrorContains(t, err, "@param id is not a parameter of /users/:userId/posts/:postId")
+
+	source = annotatedSource + `
+func (c *PostController) Routes() []forge.RouteMetadata { return nil }
+`
+	_, err = generateRoutes(writeController(t, source))
+	assert.ErrorContains(t, err, "PostController has @route annotations but already defines Routes()")
+}
+
+func TestGenerateRoutesRemovesStaleFile(t *testing.T) {
+	dir := writeController(t, "package api\n")
+	stale := filepath.Join(dir, generatedRoutesFile)
+	require.NoError(t, os.WriteFile(stale, []byte("package api\n"), 0644))
+
+	count, err := generateRoutes(dir)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	assert.NoFileExists(t, stale)
+}
+
+func replaceOnce(t *testing.T, s, old, new string) string {
+	t.Helper()
+	require.Contains(t, s, old)
+	return strings.Replace(s, old, new, 1)
+}
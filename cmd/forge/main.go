@@ -91,7 +91,58 @@ func init() {
 		Use:   "serve",
 		Short: "Start the development server",
 		Run: func(cmd *cobra.Command, args []string) {
-			startServer()
+			env, _ := cmd.Flags().GetString("env")
+			startServer(env)
+		},
+	}
+
+	serveCmd.Flags().String("env", "", "Environment profile to load (overrides FORGE_ENV)")
+
+	routesCmd := &cobra.Command{
+		Use:   "routes [package]",
+		Short: "List the routes registered by the application",
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			pkg := "."
+			if len(args) == 1 {
+				pkg = args[0]
+			}
+			method, _ := cmd.Flags().GetString("method")
+			prefix, _ := cmd.Flags().GetString("prefix")
+			asJSON, _ := cmd.Flags().GetBool("json")
+
+			if err := listRoutes(pkg, method, prefix, asJSON); err != nil {
+				fmt.Printf("Error listing routes: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	routesCmd.Flags().String("method", "", "Only show routes for this HTTP method")
+	routesCmd.Flags().String("prefix", "", "Only show routes whose path starts with this prefix")
+	routesCmd.Flags().Bool("json", false, "Print the routes as JSON")
+
+	generateRoutesCmd := &cobra.Command{
+		Use:   "generate:routes [dir...]",
+		Short: "Generate route registration from @route annotations",
+		Long: `Parses the controllers in each directory (default ".") and writes their
+@route, @desc, @body, @param and @response annotations to ` + generatedRoutesFile + `.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) == 0 {
+				args = []string{"."}
+			}
+			for _, dir := range args {
+				count, err := generateRoutes(dir)
+				if err != nil {
+					fmt.Printf("Error generating routes: %v\n", err)
+					os.Exit(1)
+				}
+				if count == 0 {
+					fmt.Printf("%s: no @route annotations found\n", dir)
+					continue
+				}
+				fmt.Printf("%s: wrote %d routes to %s\n", dir, count, generatedRoutesFile)
+			}
 		},
 	}
 
@@ -100,9 +151,16 @@ func init() {
 	rootCmd.AddCommand(makeModelCmd)
 	rootCmd.AddCommand(makeMicroserviceCmd)
 	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(routesCmd)
+	rootCmd.AddCommand(generateRoutesCmd)
 }
 
-func startServer() {
+func startServer(env string) {
+	// The application runs in a child process, so the profile is handed over
+	// through the environment where forge.LoadConfig picks it up.
+	if env != "" {
+		os.Setenv("FORGE_ENV", env)
+	}
 
 	app, err := forge.New(&forge.Config{
 		Name:        "Forge App",
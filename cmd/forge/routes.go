@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/BisiOlaYemi/forge/pkg/forge"
+)
+
+// routesTimeout bounds how long the built application may take to write
+// its route table.
+const routesTimeout = 30 * time.Second
+
+// listRoutes builds and runs the project's main package in development with
+// forge.RoutesFileEnv set, so the application writes its route table and
+// exits instead of listening, then prints the table. An application that
+// starts serving anyway, for example because it hardcodes the production
+// environment, is stopped after routesTimeout.
+func listRoutes(pkg, method, prefix string, asJSON bool) error {
+	dir, err := os.MkdirTemp("", "forge-routes-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	binary := filepath.Join(dir, "app")
+	var stderr bytes.Buffer
+	build := exec.Command("go", "build", "-o", binary, pkg)
+	build.Stderr = &stderr
+	if err := build.Run(); err != nil {
+		return fmt.Errorf("failed to build %s: %w\n%s", pkg, err, stderr.String())
+	}
+
+	file := filepath.Join(dir, "routes.json")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), routesTimeout)
+	defer cancel()
+	stderr.Reset()
+	cmd := exec.CommandContext(ctx, binary)
+	cmd.Env = append(os.Environ(), "FORGE_ENV="+forge.EnvDevelopment, forge.RoutesFileEnv+"="+file)
+	cmd.Stdout = io.Discard
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("%s did not write its routes within %s; make sure it doesn't force the production environment", pkg, routesTimeout)
+		}
+		return fmt.Errorf("failed to run %s: %w\n%s", pkg, err, stderr.String())
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return fmt.Errorf("%s exited without starting the application; make sure main calls app.Start()", pkg)
+	}
+
+	var routes []forge.Route
+	if err := json.Unmarshal(data, &routes); err != nil {
+		return fmt.Errorf("failed to read routes: %w", err)
+	}
+	routes = filterRoutes(routes, method, prefix)
+
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(routes)
+	}
+	return printRoutes(os.Stdout, routes)
+}
+
+func filterRoutes(routes []forge.Route, method, prefix string) []forge.Route {
+	filtered := make([]forge.Route, 0, len(routes))
+	for _, route := range routes {
+		if method != "" && !strings.EqualFold(route.Method, method) {
+			continue
+		}
+		if !strings.HasPrefix(route.Path, prefix) {
+			continue
+		}
+		filtered = append(filtered, route)
+	}
+	return filtered
+}
+
+func printRoutes(w io.Writer, routes []forge.Route) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "METHOD\tPATH\tHANDLER\tMIDDLEWARE\tVERSION")
+	for _, route := range routes {
+		handler := route.Handler
+		if route.Controller != "" {
+			handler = route.Controller + "." + route.Handler
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			route.Method, route.Path, handler, strings.Join(route.Middleware, ", "), route.Version)
+	}
+	return tw.Flush()
+}
@@ -39,7 +39,7 @@ func createNewProject(name string) error {
 		filepath.Join(name, "database", "migrations"),
 		filepath.Join(name, "database", "seeders"),
 		filepath.Join(name, "routes"),
-		filepath.Join(name, "templates"),
+		filepath.Join(name, "templates", "layouts"),
 		filepath.Join(name, "storage", "logs"),
 		filepath.Join(name, "storage", "uploads"),
 	}
@@ -60,32 +60,21 @@ import (
 )
 
 func main() {
-	// Create a new Forge application
-	app, err := forge.New(&forge.Config{
-		Name:        "` + name + `",
-		Version:     "1.0.0",
-		Description: "A Forge application",
-		Server: forge.ServerConfig{
-			Host:     "localhost",
-			Port:     3000,
-			BasePath: "/",
-		},
-		Database: forge.DatabaseConfig{
-			Driver: "sqlite",  
-			Name:   "forge.db",
-			// Uncomment these for other database types
-			// Host:     "localhost",
-			// Port:     3306,  
-			// Username: "forge_user",
-			// Password: "forge_password",
-		},
-	})
+	// Create a new Forge application from config/forge.yaml.
+	// Any value can be overridden with a FORGE_* environment variable,
+	// e.g. FORGE_SERVER_PORT=8080 or FORGE_DATABASE_DEFAULT_PASSWORD=secret.
+	// Secrets can also be referenced as ${DB_PASSWORD} or read from files
+	// with password_file: /run/secrets/db_password.
+	app, err := forge.NewFromFile("config/forge.yaml")
 	if err != nil {
 		log.Fatalf("Failed to create application: %v", err)
 	}
 
-	// Register controllers
-	// app.RegisterController(&UserController{})
+	// Register services with app.Provide and controllers with app.RegisterController;
+	// exported controller fields are filled from the provided services.
+	// if err := app.RegisterController(&UserController{}); err != nil {
+	// 	log.Fatalf("Failed to register controller: %v", err)
+	// }
 
 	// Start the server
 	fmt.Printf("Server starting on http://localhost:3000\n")
@@ -116,10 +105,52 @@ app:
 server:
   host: "localhost"
   port: 3000
+  # address: "unix:///run/app.sock"  # overrides host/port; also "systemd" for socket activation
   base_path: "/"
   read_timeout: 10s
   write_timeout: 10s
   idle_timeout: 120s
+  body_limit: 4194304
+  # trusted_proxies: ["10.0.0.0/8"]
+  # proxy_header: "X-Forwarded-For"
+  handle_signals: true
+  shutdown_timeout: 30s
+  # tls:
+  #   cert_file: "certs/server.crt"
+  #   key_file: "certs/server.key"
+  #   min_version: "1.2"
+  #   client_ca_file: ""
+  #   redirect_addr: ":80"
+
+# Health Probes
+health:
+  liveness_path: "/healthz"
+  readiness_path: "/readyz"
+  timeout: 2s
+  cache_ttl: 5s
+
+# Feature Flags (provider: memory, file or database)
+flags:
+  provider: "memory"
+  refresh_interval: 30s
+  # file: "config/flags.yaml"
+  # table: "feature_flags"
+  flags:
+    new-checkout:
+      enabled: false
+      # percentage: 25
+      # users: ["42"]
+
+# Admin server with pprof, config dump and runtime controls, bound to localhost
+# admin_server:
+#   enabled: true
+#   addr: "127.0.0.1:9090"
+#   token: "${ADMIN_TOKEN}"
+
+# Config sections of modules mounted with app.Mount
+# modules:
+#   billing:
+#     currency: "EUR"
 
 # Database Configuration
 database:
@@ -131,7 +162,7 @@ database:
     # host: "localhost"
     # port: 3306  
     # username: "forge_user"
-    # password: "forge_password"
+    # password: "${DB_PASSWORD}"  # or password_file: /run/secrets/db_password
     # ssl_mode: "disable" 
     # charset: "utf8mb4"
     # timezone: "Local"
@@ -141,11 +172,19 @@ database:
     slow_threshold: 200ms
     log_level: "info" 
     debug: false
+    # Read replicas inherit every setting they don't override
+    # replicas:
+    #   - host: "replica-1"
+  # Additional connections are available through app.DBNamed("analytics")
+  # analytics:
+  #   driver: "postgres"
+  #   host: "analytics-db"
+  #   name: "analytics"
 
 
 auth:
   jwt:
-    secret_key: "change-this-to-your-personal-jwt-secret-key"
+    secret_key: "${JWT_SECRET:-change-this-to-your-personal-jwt-secret-key}"
     expiration: 86400 
     refresh_expiration: 604800 
     signing_method: "HS256" 
@@ -155,13 +194,44 @@ view:
   engine: "go-template" 
   directory: "templates"
   extension: ".gohtml"
-  cache: true
+  layout: "layouts/main"
 `
 
 	if err := os.WriteFile(filepath.Join(name, "config", "forge.yaml"), []byte(configContent), 0644); err != nil {
 		return fmt.Errorf("failed to create forge.yaml: %w", err)
 	}
 
+	layoutContent := `<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="UTF-8">
+	<title>{{block "title" .}}` + name + `{{end}}</title>
+	<link rel="stylesheet" href="{{asset "css/app.css"}}">
+</head>
+<body>
+	{{template "content" .}}
+</body>
+</html>
+`
+
+	if err := os.WriteFile(filepath.Join(name, "templates", "layouts", "main.gohtml"), []byte(layoutContent), 0644); err != nil {
+		return fmt.Errorf("failed to create layout: %w", err)
+	}
+
+	gitignoreContent := `# Local configuration overrides
+config/forge.local.yaml
+
+# Build output and runtime data
+/` + name + `
+*.db
+storage/logs/
+storage/uploads/
+`
+
+	if err := os.WriteFile(filepath.Join(name, ".gitignore"), []byte(gitignoreContent), 0644); err != nil {
+		return fmt.Errorf("failed to create .gitignore: %w", err)
+	}
+
 	modContent := `module ` + name + `
 
 go 1.21
@@ -197,6 +267,13 @@ This project uses SQLite by default, which requires no additional setup. To use
 2. Choose from: sqlite, mysql, postgres, sqlserver
 3. Provide connection details as required
 
+## Environments
+
+Configuration is layered: ` + "`config/forge.yaml`" + ` is loaded first, then
+` + "`config/forge.<env>.yaml`" + `, then the git-ignored ` + "`config/forge.local.yaml`" + `,
+and finally any ` + "`FORGE_*`" + ` environment variables. Select the environment with
+` + "`FORGE_ENV=production`" + ` or ` + "`forge serve --env production`" + `.
+
 ## Creating Controllers and Models
 
 Generate new controllers:
@@ -230,113 +307,87 @@ func generateController(name string) error {
 		name += "Controller"
 	}
 
+	resource := strings.TrimSuffix(name, "Controller")
+	plural := strings.ToLower(resource) + "s"
+
 	controllerContent := `package controllers
 
 import (
+	"errors"
+
+	"` + getCurrentModuleName() + `/app/models"
 	"github.com/BisiOlaYemi/forge/pkg/forge"
+	"gorm.io/gorm"
 )
 
-// ` + name + ` handles requests related to ` + strings.TrimSuffix(name, "Controller") + `
+// ` + name + ` handles requests related to ` + resource + `. Mount it with
+//
+//	app.Resource("/` + plural + `", &controllers.` + name + `{})
 type ` + name + ` struct {
 	forge.Controller
+	DB *gorm.DB ` + "`inject:\"\"`" + `
 }
 
-// HandleGet` + strings.TrimSuffix(name, "Controller") + ` handles getting a ` + strings.TrimSuffix(name, "Controller") + `
-// @route GET /` + strings.ToLower(strings.TrimSuffix(name, "Controller")) + `s
-// @desc Get all ` + strings.ToLower(strings.TrimSuffix(name, "Controller")) + `s
-// @response 200 []` + strings.TrimSuffix(name, "Controller") + `
-func (c *` + name + `) HandleGet` + strings.TrimSuffix(name, "Controller") + `s(ctx *forge.Context) error {
-	var items []interface{}
-	if err := c.App().DB().Find(&items).Error; err != nil {
-		return ctx.Status(500).JSON(map[string]string{"error": err.Error()})
+// Index lists the ` + plural + `
+func (c *` + name + `) Index(ctx *forge.Context) ([]models.` + resource + `, error) {
+	items := []models.` + resource + `{}
+	if err := c.DB.Find(&items).Error; err != nil {
+		return nil, err
 	}
-	return ctx.JSON(items)
+	return items, nil
 }
 
-// HandleGet` + strings.TrimSuffix(name, "Controller") + `ByID handles getting a ` + strings.TrimSuffix(name, "Controller") + ` by ID
-// @route GET /` + strings.ToLower(strings.TrimSuffix(name, "Controller")) + `s/:id
-// @desc Get a ` + strings.ToLower(strings.TrimSuffix(name, "Controller")) + ` by ID
-// @param id path int true "` + strings.TrimSuffix(name, "Controller") + ` ID"
-// @response 200 ` + strings.TrimSuffix(name, "Controller") + `
-func (c *` + name + `) HandleGet` + strings.TrimSuffix(name, "Controller") + `ByID(ctx *forge.Context) error {
-	id := ctx.Param("id")
-	var item interface{}
-	if err := c.App().DB().First(&item, id).Error; err != nil {
-		return ctx.Status(404).JSON(map[string]string{"error": "Not found"})
+// Show returns a ` + strings.ToLower(resource) + ` by ID
+func (c *` + name + `) Show(ctx *forge.Context) (*models.` + resource + `, error) {
+	return c.find(ctx.Param("id"))
+}
+
+// Create creates a new ` + strings.ToLower(resource) + `
+func (c *` + name + `) Create(ctx *forge.Context, req *Create` + resource + `Request) (*models.` + resource + `, error) {
+	item := &models.` + resource + `{}
+	// Copy the request fields onto item here
+
+	if err := c.DB.Create(item).Error; err != nil {
+		return nil, err
 	}
-	return ctx.JSON(item)
+	return item, nil
 }
 
-// HandleCreate` + strings.TrimSuffix(name, "Controller") + ` handles creating a new ` + strings.TrimSuffix(name, "Controller") + `
-// @route POST /` + strings.ToLower(strings.TrimSuffix(name, "Controller")) + `s
-// @desc Create a new ` + strings.ToLower(strings.TrimSuffix(name, "Controller")) + `
-// @body Create` + strings.TrimSuffix(name, "Controller") + `Request
-// @response 201 ` + strings.TrimSuffix(name, "Controller") + `
-func (c *` + name + `) HandleCreate` + strings.TrimSuffix(name, "Controller") + `(ctx *forge.Context) error {
-	var req Create` + strings.TrimSuffix(name, "Controller") + `Request
-	
-	if err := ctx.BodyParser(&req); err != nil {
-		return ctx.Status(400).JSON(map[string]string{"error": err.Error()})
+// Update updates a ` + strings.ToLower(resource) + `
+func (c *` + name + `) Update(ctx *forge.Context, req *Update` + resource + `Request) (*models.` + resource + `, error) {
+	item, err := c.find(ctx.Param("id"))
+	if err != nil {
+		return nil, err
 	}
-	
-	// Create record (replace with your model)
-	item := map[string]interface{}{"name": req.Name}
-	
-	if err := c.App().DB().Create(&item).Error; err != nil {
-		return ctx.Status(500).JSON(map[string]string{"error": err.Error()})
+	// Copy the request fields onto item here
+
+	if err := c.DB.Save(item).Error; err != nil {
+		return nil, err
 	}
-	
-	return ctx.Status(201).JSON(item)
+	return item, nil
 }
 
-// HandleUpdate` + strings.TrimSuffix(name, "Controller") + ` handles updating a ` + strings.TrimSuffix(name, "Controller") + `
-// @route PUT /` + strings.ToLower(strings.TrimSuffix(name, "Controller")) + `s/:id
-// @desc Update a ` + strings.ToLower(strings.TrimSuffix(name, "Controller")) + `
-// @param id path int true "` + strings.TrimSuffix(name, "Controller") + ` ID"
-// @body Update` + strings.TrimSuffix(name, "Controller") + `Request
-// @response 200 ` + strings.TrimSuffix(name, "Controller") + `
-func (c *` + name + `) HandleUpdate` + strings.TrimSuffix(name, "Controller") + `(ctx *forge.Context) error {
-	id := ctx.Param("id")
-	
-	var req Update` + strings.TrimSuffix(name, "Controller") + `Request
-	if err := ctx.BodyParser(&req); err != nil {
-		return ctx.Status(400).JSON(map[string]string{"error": err.Error()})
-	}
-	
-	// Update record (replace with your model)
-	var item interface{}
-	if err := c.App().DB().First(&item, id).Error; err != nil {
-		return ctx.Status(404).JSON(map[string]string{"error": "Not found"})
+// Destroy deletes a ` + strings.ToLower(resource) + `
+func (c *` + name + `) Destroy(ctx *forge.Context) error {
+	result := c.DB.Delete(&models.` + resource + `{}, ctx.Param("id"))
+	if result.Error != nil {
+		return result.Error
 	}
-	
-	// Update fields based on request
-	
-	if err := c.App().DB().Save(&item).Error; err != nil {
-		return ctx.Status(500).JSON(map[string]string{"error": err.Error()})
+	if result.RowsAffected == 0 {
+		return forge.NotFoundError("` + strings.ToLower(resource) + `")
 	}
-	
-	return ctx.JSON(item)
+	return ctx.SendStatus(204)
 }
 
-// HandleDelete` + strings.TrimSuffix(name, "Controller") + ` handles deleting a ` + strings.TrimSuffix(name, "Controller") + `
-// @route DELETE /` + strings.ToLower(strings.TrimSuffix(name, "Controller")) + `s/:id
-// @desc Delete a ` + strings.ToLower(strings.TrimSuffix(name, "Controller")) + `
-// @param id path int true "` + strings.TrimSuffix(name, "Controller") + ` ID"
-// @response 204
-func (c *` + name + `) HandleDelete` + strings.TrimSuffix(name, "Controller") + `(ctx *forge.Context) error {
-	id := ctx.Param("id")
-	
-	// Delete record (replace with your model)
-	var item interface{}
-	if err := c.App().DB().First(&item, id).Error; err != nil {
-		return ctx.Status(404).JSON(map[string]string{"error": "Not found"})
-	}
-	
-	if err := c.App().DB().Delete(&item).Error; err != nil {
-		return ctx.Status(500).JSON(map[string]string{"error": err.Error()})
+func (c *` + name + `) find(id string) (*models.` + resource + `, error) {
+	var item models.` + resource + `
+	if err := c.DB.First(&item, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, forge.NotFoundError("` + strings.ToLower(resource) + `")
+		}
+		return nil, err
 	}
-	
-	return ctx.Status(204).Send([]byte{})
+	return &item, nil
 }
 `
 
@@ -370,12 +421,30 @@ type Update` + strings.TrimSuffix(name, "Controller") + `Request struct {
 }
 `
 
+	// The controller uses models.<Resource>: keep a model generated with
+	// make:model, and create a minimal one otherwise.
+	modelPath := filepath.Join("app", "models", strings.ToLower(resource)+".go")
+	if existing, err := os.ReadFile(modelPath); err == nil {
+		if !strings.Contains(string(existing), "type "+resource+" struct") {
+			return fmt.Errorf("%s exists but doesn't declare the %s model the controller uses", modelPath, resource)
+		}
+		modelContent = ""
+	}
+
+	for _, dir := range []string{filepath.Join("app", "controllers"), filepath.Join("app", "models")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", dir, err)
+		}
+	}
+
 	if err := os.WriteFile(filepath.Join("app", "controllers", strings.ToLower(name)+".go"), []byte(controllerContent), 0644); err != nil {
 		return fmt.Errorf("failed to create controller file: %w", err)
 	}
 
-	if err := os.WriteFile(filepath.Join("app", "models", strings.ToLower(strings.TrimSuffix(name, "Controller"))+".go"), []byte(modelContent), 0644); err != nil {
-		return fmt.Errorf("failed to create model file: %w", err)
+	if modelContent != "" {
+		if err := os.WriteFile(modelPath, []byte(modelContent), 0644); err != nil {
+			return fmt.Errorf("failed to create model file: %w", err)
+		}
 	}
 
 	if err := os.WriteFile(filepath.Join("app", "controllers", strings.ToLower(name)+"_types.go"), []byte(typesContent), 0644); err != nil {
@@ -383,6 +452,7 @@ type Update` + strings.TrimSuffix(name, "Controller") + `Request struct {
 	}
 
 	fmt.Printf("Generated controller: %s\n", name)
+	fmt.Printf("Mount it with app.Resource(\"/%s\", &controllers.%s{})\n", plural, name)
 	return nil
 }
 
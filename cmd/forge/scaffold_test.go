@@ -0,0 +1,54 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// inTempDir runs the rest of the test in an empty directory.
+func inTempDir(t *testing.T) {
+	t.Helper()
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() {
+		os.Chdir(wd)
+	})
+}
+
+func TestGenerateControllerCreatesItsModel(t *testing.T) {
+	inTempDir(t)
+
+	require.NoError(t, generateController("post"))
+	for _, file := range []string{
+		"app/controllers/postcontroller.go",
+		"app/controllers/postcontroller_types.go",
+		"app/models/post.go",
+	} {
+		_, err := parser.ParseFile(token.NewFileSet(), file, nil, 0)
+		assert.NoError(t, err, file)
+	}
+}
+
+func TestGenerateControllerKeepsExistingModel(t *testing.T) {
+	inTempDir(t)
+	require.NoError(t, os.MkdirAll(filepath.Join("app", "models"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join("database", "migrations"), 0755))
+	require.NoError(t, generateModel("Post"))
+	model, err := os.ReadFile(filepath.Join("app", "models", "post.go"))
+	require.NoError(t, err)
+
+	require.NoError(t, generateController("post"))
+	kept, err := os.ReadFile(filepath.Join("app", "models", "post.go"))
+	require.NoError(t, err)
+	assert.Equal(t, string(model), string(kept))
+
+	require.NoError(t, os.WriteFile(filepath.Join("app", "models", "comment.go"), []byte("package models\n"), 0644))
+	assert.ErrorContains(t, generateController("comment"), "doesn't declare the Comment model")
+}
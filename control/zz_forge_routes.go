@@ -0,0 +1,31 @@
+// Code generated by forge generate:routes. DO NOT EDIT.
+
+package control
+
+import (
+	"github.com/BisiOlaYemi/forge/pkg/forge"
+)
+
+// Routes returns the routes annotated on UserController.
+func (c *UserController) Routes() []forge.RouteMetadata {
+	return []forge.RouteMetadata{
+		{
+			Handler:     "HandlePostLogin",
+			Method:      "POST",
+			Path:        "/login",
+			Description: "Authenticate a user",
+			RequestBody: LoginRequest{},
+			Response:    LoginResponse{},
+		},
+		{
+			Handler:     "HandleGetUser",
+			Method:      "GET",
+			Path:        "/users/:id",
+			Description: "Get a user by ID",
+			Params: []forge.RouteParam{
+				{Name: "id", In: "path", Type: "integer", Required: true, Description: "User ID"},
+			},
+			Response: User{},
+		},
+	}
+}
@@ -0,0 +1,183 @@
+package forge
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+	"time"
+
+	"github.com/BisiOlaYemi/forge/pkg/forge/logger"
+)
+
+// DefaultAdminAddr keeps the admin server on the loopback interface.
+const DefaultAdminAddr = "127.0.0.1:9090"
+
+// AdminServerConfig configures the admin server, a second listener for
+// diagnostics and runtime controls that stays off the public port:
+//
+//	/debug/pprof/   net/http/pprof
+//	/config         effective configuration with secrets redacted
+//	/routes         app.Routes()
+//	/plugins        loaded plugins
+//	/queue          queue stats
+//	/log-level      GET the level, PUT {"level": "debug"} to change it
+//
+// Every request must send "Authorization: Bearer <token>".
+type AdminServerConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Addr    string `yaml:"addr"` // defaults to DefaultAdminAddr
+	Token   string `yaml:"token" secret:"true"`
+}
+
+type pluginInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Version     string `json:"version"`
+}
+
+// startAdmin starts the admin server when it is enabled.
+func (app *Application) startAdmin() error {
+	config := app.config.AdminServer
+	if !config.Enabled {
+		return nil
+	}
+
+	addr := config.Addr
+	if addr == "" {
+		addr = DefaultAdminAddr
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to start admin server: %w", err)
+	}
+	if tcp, ok := ln.Addr().(*net.TCPAddr); ok && !tcp.IP.IsLoopback() {
+		app.logger.Warn("Admin server listens on %s, which is reachable from other hosts", tcp)
+	}
+
+	app.serveAdmin(ln)
+	return nil
+}
+
+func (app *Application) serveAdmin(ln net.Listener) {
+	server := &http.Server{
+		Handler:           app.adminHandler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	app.mu.Lock()
+	app.adminServer = server
+	app.mu.Unlock()
+
+	go func() {
+		if err := server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			app.logger.Error("Admin server failed: %v", err)
+		}
+	}()
+
+	app.logger.Info("Admin server listening on %s", ln.Addr())
+}
+
+func (app *Application) shutdownAdmin(ctx context.Context) error {
+	app.mu.RLock()
+	server := app.adminServer
+	app.mu.RUnlock()
+
+	if server == nil {
+		return nil
+	}
+	return server.Shutdown(ctx)
+}
+
+func (app *Application) adminHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	mux.HandleFunc("GET /config", func(w http.ResponseWriter, r *http.Request) {
+		writeAdminJSON(w, http.StatusOK, app.config.Redacted())
+	})
+
+	mux.HandleFunc("GET /routes", func(w http.ResponseWriter, r *http.Request) {
+		writeAdminJSON(w, http.StatusOK, app.Routes())
+	})
+
+	mux.HandleFunc("GET /plugins", func(w http.ResponseWriter, r *http.Request) {
+		plugins := []pluginInfo{}
+		if app.plugins != nil {
+			for _, p := range app.plugins.List() {
+				plugins = append(plugins, pluginInfo{Name: p.Name(), Description: p.Description(), Version: p.Version()})
+			}
+		}
+		writeAdminJSON(w, http.StatusOK, plugins)
+	})
+
+	mux.HandleFunc("GET /queue", func(w http.ResponseWriter, r *http.Request) {
+		if app.queue == nil {
+			writeAdminJSON(w, http.StatusNotFound, H{"error": "no queue is configured"})
+			return
+		}
+		stats, err := app.queue.Stats(r.Context())
+		if err != nil {
+			writeAdminJSON(w, http.StatusServiceUnavailable, H{"error": err.Error()})
+			return
+		}
+		writeAdminJSON(w, http.StatusOK, stats)
+	})
+
+	mux.HandleFunc("GET /log-level", func(w http.ResponseWriter, r *http.Request) {
+		writeAdminJSON(w, http.StatusOK, H{"level": app.logger.Level().String()})
+	})
+
+	mux.HandleFunc("PUT /log-level", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Level string `json:"level"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeAdminJSON(w, http.StatusBadRequest, H{"error": "invalid request body"})
+			return
+		}
+		level := logger.ParseLevel(body.Level)
+		if level.String() != strings.ToUpper(body.Level) {
+			writeAdminJSON(w, http.StatusBadRequest, H{"error": fmt.Sprintf("unknown log level %q", body.Level)})
+			return
+		}
+
+		previous := app.logger.Level()
+		app.logger.SetLevel(level)
+		app.logger.Warn("Log level changed from %s to %s via the admin server", previous, level)
+		writeAdminJSON(w, http.StatusOK, H{"level": level.String()})
+	})
+
+	return app.adminAuth(mux)
+}
+
+// adminAuth rejects requests without the admin bearer token.
+func (app *Application) adminAuth(next http.Handler) http.Handler {
+	token := []byte(app.config.AdminServer.Token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || len(token) == 0 || subtle.ConstantTimeCompare([]byte(given), token) != 1 {
+			writeAdminJSON(w, http.StatusUnauthorized, H{"error": "invalid admin token"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeAdminJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
@@ -0,0 +1,117 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/BisiOlaYemi/forge/pkg/forge/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func adminRequest(t *testing.T, handler http.Handler, method, path, token, body string) (int, map[string]interface{}, string) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	var decoded map[string]interface{}
+	json.Unmarshal(rec.Body.Bytes(), &decoded)
+	return rec.Code, decoded, rec.Body.String()
+}
+
+func TestAdminServerRequiresToken(t *testing.T) {
+	_, err := New(&Config{AdminServer: AdminServerConfig{Enabled: true}})
+	assert.ErrorContains(t, err, "token")
+
+	app := newTestApp(t, &Config{AdminServer: AdminServerConfig{Enabled: true, Token: "s3cret"}})
+	handler := app.adminHandler()
+
+	status, _, _ := adminRequest(t, handler, http.MethodGet, "/routes", "", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+	status, _, _ = adminRequest(t, handler, http.MethodGet, "/routes", "wrong", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+	status, _, _ = adminRequest(t, handler, http.MethodGet, "/routes", "s3cret", "")
+	assert.Equal(t, http.StatusOK, status)
+}
+
+func TestAdminServerEndpoints(t *testing.T) {
+	app := newTestApp(t, &Config{
+		Database:    DatabaseConfig{Driver: "sqlite", Name: ":memory:", Password: "db-password"},
+		AdminServer: AdminServerConfig{Enabled: true, Token: "s3cret"},
+		Modules: map[string]interface{}{
+			"billing": map[string]interface{}{"currency": "eur", "stripe": map[string]interface{}{"api_key": "sk_live_123"}},
+		},
+	})
+	app.RegisterController(&WidgetController{})
+	handler := app.adminHandler()
+
+	status, _, body := adminRequest(t, handler, http.MethodGet, "/config", "s3cret", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.NotContains(t, body, "db-password")
+	assert.NotContains(t, body, "s3cret")
+	assert.NotContains(t, body, "sk_live_123", "module sections are redacted")
+	assert.Contains(t, body, `"currency": "eur"`)
+
+	status, _, body = adminRequest(t, handler, http.MethodGet, "/routes", "s3cret", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "/widget")
+
+	status, _, body = adminRequest(t, handler, http.MethodGet, "/plugins", "s3cret", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, "[]", body)
+
+	status, _, _ = adminRequest(t, handler, http.MethodGet, "/queue", "s3cret", "")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, _, body = adminRequest(t, handler, http.MethodGet, "/debug/pprof/", "s3cret", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "goroutine")
+}
+
+func TestAdminServerChangesLogLevel(t *testing.T) {
+	app := newTestApp(t, &Config{AdminServer: AdminServerConfig{Enabled: true, Token: "s3cret"}})
+	handler := app.adminHandler()
+	derived := app.WithLogField("component", "test")
+
+	status, decoded, _ := adminRequest(t, handler, http.MethodGet, "/log-level", "s3cret", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "INFO", decoded["level"])
+
+	status, decoded, _ = adminRequest(t, handler, http.MethodPut, "/log-level", "s3cret", `{"level": "debug"}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "DEBUG", decoded["level"])
+	assert.Equal(t, logger.LevelDebug, app.Logger().Level())
+	assert.Equal(t, logger.LevelDebug, derived.Level(), "derived loggers follow the level")
+
+	status, _, _ = adminRequest(t, handler, http.MethodPut, "/log-level", "s3cret", `{"level": "verbose"}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, logger.LevelDebug, app.Logger().Level())
+}
+
+func TestAdminServerStopsOnShutdown(t *testing.T) {
+	app := newTestApp(t, &Config{AdminServer: AdminServerConfig{Enabled: true, Token: "s3cret"}})
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	app.serveAdmin(ln)
+
+	req, err := http.NewRequest(http.MethodGet, "http://"+ln.Addr().String()+"/log-level", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.NoError(t, app.ShutdownWithContext(context.Background()))
+	_, err = net.Dial("tcp", ln.Addr().String())
+	assert.Error(t, err)
+}
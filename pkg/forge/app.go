@@ -1,18 +1,23 @@
 package forge
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
-	"unicode"
+	"time"
 
 	"github.com/BisiOlaYemi/forge/pkg/forge/auth"
+	"github.com/BisiOlaYemi/forge/pkg/forge/flags"
 	"github.com/BisiOlaYemi/forge/pkg/forge/logger"
 	"github.com/BisiOlaYemi/forge/pkg/forge/mailer"
 	"github.com/BisiOlaYemi/forge/pkg/forge/plugin"
 	"github.com/BisiOlaYemi/forge/pkg/forge/queue"
+	"github.com/BisiOlaYemi/forge/pkg/forge/secrets"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -24,36 +29,140 @@ import (
 var validate = validator.New()
 
 type Application struct {
-	config      *Config
-	server      *fiber.App
-	validator   *validator.Validate
-	database    *Database
-	auth        *auth.Auth
-	mailer      *mailer.Mailer
-	queue       *queue.Queue
-	plugins     *plugin.Manager
-	logger      *logger.Logger
-	mu          sync.RWMutex
-	controllers []interface{}
+	config         *Config
+	server         *fiber.App
+	validator      *validator.Validate
+	database       *Database
+	databases      map[string]*Database
+	auth           *auth.Auth
+	mailer         *mailer.Mailer
+	queue          *queue.Queue
+	plugins        *plugin.Manager
+	logger         *logger.Logger
+	mu             sync.RWMutex
+	controllers    []controllerEntry
+	routeOwners    map[string]string
+	middleware     []MiddlewareFunc
+	handlerRoutes  map[string]Route
+	renderers      []registeredRenderer
+	renderersMu    sync.RWMutex
+	views          *Views
+	hub            *Hub
+	events         *Events
+	startHooks     []Hook
+	shutdownHooks  []Hook
+	container      container
+	healthChecks   []healthCheck
+	health         healthCache
+	closing        chan struct{}
+	closeOnce      sync.Once
+	redirectServer *http.Server
+	adminServer    *http.Server
+	flags          *flags.Manager
+	modules        []string
+	migrations     []Migration
 }
 
 type Config struct {
 	Name        string
 	Version     string
 	Description string
+	Environment string
+	Debug       bool
 	Server      ServerConfig
 	Database    DatabaseConfig
+	// Databases holds named connections; the "default" entry takes
+	// precedence over Database and backs DB().
+	Databases   map[string]DatabaseConfig
 	Auth        auth.Config
 	Mailer      mailer.Config
 	Queue       queue.Config
 	CORS        CORSConfig
+	View        ViewConfig
+	WebSocket   WebSocketConfig
+	SSE         SSEConfig
+	Health      HealthConfig
+	Flags       FlagsConfig
+	AdminServer AdminServerConfig
 	LogLevel    string
+
+	// Modules holds the config sections of mounted modules by module name.
+	Modules map[string]interface{}
+}
+
+// Redacted returns a copy of the config with every field tagged
+// `secret:"true"` masked, so it can be logged or dumped safely.
+func (c Config) Redacted() Config {
+	return secrets.Redact(c).(Config)
+}
+
+// String formats the config with secrets masked.
+func (c Config) String() string {
+	type plain Config
+	return fmt.Sprintf("%+v", plain(c.Redacted()))
+}
+
+// GoString keeps %#v from printing secrets.
+func (c Config) GoString() string {
+	type plain Config
+	return fmt.Sprintf("%#v", plain(c.Redacted()))
 }
 
 type ServerConfig struct {
-	Host     string
-	Port     int
-	BasePath string
+	// Address overrides Host and Port: host:port, unix:///run/app.sock or
+	// systemd[:name] for a socket passed by systemd socket activation.
+	Address         string        `yaml:"address"`
+	Host            string        `yaml:"host"`
+	Port            int           `yaml:"port"`
+	SocketMode      string        `yaml:"socket_mode"` // octal permissions of a unix socket, e.g. "0660"
+	BasePath        string        `yaml:"base_path"`
+	HandleSignals   bool          `yaml:"handle_signals"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	TLS             TLSConfig     `yaml:"tls"`
+
+	// ReadTimeout defaults to DefaultReadTimeout and IdleTimeout to
+	// DefaultIdleTimeout. WriteTimeout is unlimited unless set.
+	ReadTimeout  time.Duration `yaml:"read_timeout"`
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout"`
+
+	BodyLimit      int  `yaml:"body_limit"`       // bytes, fiber defaults to 4MB
+	MaxHeaderBytes int  `yaml:"max_header_bytes"` // request line plus headers, fiber defaults to 4KB
+	Concurrency    int  `yaml:"concurrency"`      // max simultaneous connections
+	Prefork        bool `yaml:"prefork"`
+
+	// TrustedProxies lists the IPs or CIDRs whose ProxyHeader is trusted
+	// when resolving the client IP.
+	TrustedProxies []string `yaml:"trusted_proxies"`
+	ProxyHeader    string   `yaml:"proxy_header"` // e.g. X-Forwarded-For
+}
+
+const (
+	DefaultReadTimeout = 10 * time.Second
+	DefaultIdleTimeout = 120 * time.Second
+)
+
+// fiberConfig translates the server settings into the fiber.Config used by New.
+func (c ServerConfig) fiberConfig() fiber.Config {
+	config := fiber.Config{
+		ReadTimeout:             c.ReadTimeout,
+		WriteTimeout:            c.WriteTimeout,
+		IdleTimeout:             c.IdleTimeout,
+		BodyLimit:               c.BodyLimit,
+		ReadBufferSize:          c.MaxHeaderBytes,
+		Concurrency:             c.Concurrency,
+		Prefork:                 c.Prefork,
+		ProxyHeader:             c.ProxyHeader,
+		EnableTrustedProxyCheck: len(c.TrustedProxies) > 0,
+		TrustedProxies:          c.TrustedProxies,
+	}
+	if config.ReadTimeout == 0 {
+		config.ReadTimeout = DefaultReadTimeout
+	}
+	if config.IdleTimeout == 0 {
+		config.IdleTimeout = DefaultIdleTimeout
+	}
+	return config
 }
 
 
@@ -78,17 +187,21 @@ func DefaultCORSConfig() CORSConfig {
 }
 
 func New(config *Config) (*Application, error) {
-	fiberConfig := fiber.Config{
-		AppName:      config.Name,
-		ErrorHandler: defaultErrorHandler,
+	if config.AdminServer.Enabled && config.AdminServer.Token == "" {
+		return nil, errors.New("admin server requires a token")
 	}
 
 	app := &Application{
 		config:    config,
-		server:    fiber.New(fiberConfig),
 		validator: validator.New(),
+		closing:   make(chan struct{}),
 	}
 
+	fiberConfig := config.Server.fiberConfig()
+	fiberConfig.AppName = config.Name
+	fiberConfig.ErrorHandler = app.errorHandler
+	app.server = fiber.New(fiberConfig)
+
 	// Configure logger
 	logLevel := logger.LevelInfo
 	if config.LogLevel != "" {
@@ -96,7 +209,8 @@ func New(config *Config) (*Application, error) {
 	}
 
 	log := logger.New(logger.Config{
-		Level: logLevel,
+		Level:     logLevel,
+		Colorized: !app.IsProduction(),
 	})
 	log.Info("Initializing Forge application: %s v%s", config.Name, config.Version)
 	app.logger = log
@@ -118,17 +232,31 @@ func New(config *Config) (*Application, error) {
 		ExposeHeaders:    corsConfig.ExposeHeaders,
 		MaxAge:           corsConfig.MaxAge,
 	}))
-
-	if config.Database.Driver != "" {
-		log.Info("Initializing database connection: %s", config.Database.Driver)
-		db, err := NewDatabase(&config.Database)
+	app.server.Use(app.handleMiddleware)
+	app.registerDefaultRenderers()
+	app.views = newViews(app)
+	app.hub = newHub(app)
+	app.events = newEvents(app)
+
+	app.databases = make(map[string]*Database)
+	databases := config.databaseConfigs()
+	names := make([]string, 0, len(databases))
+	for name := range databases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		dbConfig := databases[name]
+		log.Info("Initializing database connection %s: %s", name, dbConfig.Driver)
+		db, err := NewDatabase(&dbConfig)
 		if err != nil {
-			log.Error("Failed to initialize database: %v", err)
-			return nil, fmt.Errorf("failed to initialize database: %w", err)
+			log.Error("Failed to initialize database %s: %v", name, err)
+			return nil, fmt.Errorf("failed to initialize database %s: %w", name, err)
 		}
-		app.database = db
-		log.Info("Database connection established")
+		app.databases[name] = db
+		log.Info("Database connection %s established", name)
 	}
+	app.database = app.databases[DefaultDatabase]
 
 	if config.Auth.SecretKey != "" {
 		log.Info("Initializing authentication")
@@ -163,6 +291,11 @@ func New(config *Config) (*Application, error) {
 		log.Info("Message queue initialized")
 	}
 
+	if err := app.setupFlags(); err != nil {
+		log.Error("Failed to load feature flags: %v", err)
+		return nil, fmt.Errorf("failed to load feature flags: %w", err)
+	}
+
 	log.Info("Loading plugins")
 	plugins := plugin.NewManager(app, "plugins")
 	if err := plugins.LoadPlugins(); err != nil {
@@ -172,6 +305,23 @@ func New(config *Config) (*Application, error) {
 	app.plugins = plugins
 	log.Info("Plugins loaded successfully")
 
+	app.provideBuiltins()
+
+	for name, db := range app.databases {
+		if name == DefaultDatabase {
+			app.AddHealthCheck("database", db)
+		} else {
+			app.AddHealthCheck("database:"+name, db)
+		}
+	}
+	if app.queue != nil {
+		app.AddHealthCheck("queue", app.queue)
+	}
+	if app.mailer != nil {
+		app.AddHealthCheck("mailer", app.mailer)
+	}
+	app.registerHealthRoutes()
+
 	app.server.Get("/", func(c *fiber.Ctx) error {
 		return c.Type("html").SendString(`
 			<!DOCTYPE html>
@@ -196,23 +346,68 @@ func New(config *Config) (*Application, error) {
 		`)
 	})
 
+	// The generated API documentation exposes every route, so it is only
+	// served outside production.
+	if !app.IsProduction() {
+		app.server.Get("/docs", app.handleSwaggerUI)
+		app.server.Get("/docs/openapi.json", app.handleOpenAPISpec)
+	}
+
+	app.watchFlags()
 	log.Info("Forge application initialized successfully")
 	return app, nil
 }
 
 
-func defaultErrorHandler(c *fiber.Ctx, err error) error {
+func (app *Application) errorHandler(c *fiber.Ctx, err error) error {
 	code := fiber.StatusInternalServerError
+	message := err.Error()
+	body := fiber.Map{"error": true}
+
+	// Retrieve the custom status code if it's a fiber.*Error or *AppError
+	var fiberErr *fiber.Error
+	var appErr *AppError
+	switch {
+	case errors.As(err, &fiberErr):
+		code = fiberErr.Code
+	case errors.As(err, &appErr):
+		code = appErr.StatusCode
+		if appErr.Code != "" {
+			body["code"] = appErr.Code
+		}
+		if len(appErr.Details) > 0 {
+			body["details"] = appErr.Details
+		}
+		if app.IsProduction() {
+			message = appErr.Message
+		}
+	}
 
-	// Retrieve the custom status code if it's a fiber.*Error
-	if e, ok := err.(*fiber.Error); ok {
-		code = e.Code
+	// Internal errors can leak implementation details, so production only
+	// reports the status text unless debug output is switched on.
+	if code >= fiber.StatusInternalServerError && app.IsProduction() && !app.config.Debug {
+		message = http.StatusText(code)
+		delete(body, "details")
 	}
 
-	return c.Status(code).JSON(fiber.Map{
-		"error":   true,
-		"message": err.Error(),
-	})
+	body["message"] = message
+	return c.Status(code).JSON(body)
+}
+
+// Env returns the environment the application runs in, e.g. development or production.
+func (app *Application) Env() string {
+	if app.config.Environment == "" {
+		return EnvDevelopment
+	}
+	return app.config.Environment
+}
+
+func (app *Application) IsProduction() bool {
+	return app.Env() == EnvProduction
+}
+
+func (app *Application) IsDevelopment() bool {
+	return app.Env() == EnvDevelopment
 }
 
 func (app *Application) GetConfig() interface{} {
@@ -235,7 +430,36 @@ func (app *Application) GetMailer() interface{} {
 	return app.mailer
 }
 
-func (app *Application) RegisterController(controller interface{}) {
+// RegisterController mounts the controller's Handle* methods under
+// Server.BasePath. Controllers that implement Version() string are mounted
+// under that API version, e.g. /api/v1/users.
+//
+// Exported fields are filled from the dependency container first (see
+// Provide); missing or cyclic dependencies are returned as errors.
+//
+// Handlers run inside the middleware added with the controller's Use, and
+// handlers named in an optional Middleware() map[string][]MiddlewareFunc
+// method additionally inside their own middleware.
+func (app *Application) RegisterController(controller interface{}) error {
+	return app.registerController(controller, controllerEntry{version: controllerVersion(controller)})
+}
+
+func controllerVersion(controller interface{}) string {
+	if v, ok := controller.(interface{ Version() string }); ok {
+		return v.Version()
+	}
+	return ""
+}
+
+// registerController mounts controller under the base path, the entry's
+// prefix and version, wrapping its handlers in the entry's middleware, then
+// the controller's and finally the handler's own.
+func (app *Application) registerController(controller interface{}, entry controllerEntry) error {
+	requestFields, err := app.inject(controller)
+	if err != nil {
+		return err
+	}
+
 	app.mu.Lock()
 	defer app.mu.Unlock()
 
@@ -243,48 +467,268 @@ func (app *Application) RegisterController(controller interface{}) {
 		c.SetApplication(app)
 	}
 
-	app.controllers = append(app.controllers, controller)
+	entry.controller = controller
+	entry.prefix = joinPath(app.config.Server.BasePath, entry.prefix, entry.version)
+	routes, err := entry.resolveRoutes()
+	if err != nil {
+		return err
+	}
+	methodMiddleware, err := entry.methodMiddleware()
+	if err != nil {
+		return err
+	}
+	if c, ok := controller.(interface{ controllerMiddleware() []MiddlewareFunc }); ok {
+		entry.middleware = append(append([]MiddlewareFunc(nil), entry.middleware...), c.controllerMiddleware()...)
+	}
+	if err := app.claimRoutes(entry.controller, routes); err != nil {
+		return err
+	}
+	entry.routes = routes
+	app.controllers = append(app.controllers, entry)
 
-	controllerType := reflect.TypeOf(controller)
 	controllerValue := reflect.ValueOf(controller)
+	for _, route := range routes {
+		middleware := append(append([]MiddlewareFunc(nil), entry.middleware...), methodMiddleware[route.method.Name]...)
+		var handler fiber.Handler
+		if route.websocket {
+			handler = app.createWSHandler(route.method, controllerValue, requestFields, middleware)
+		} else {
+			handler = app.createHandlerFunc(route.method, controllerValue, requestFields, middleware)
+		}
+
+		// Route is Registered with the fiber app
+		app.server.Add(route.HTTPMethod, route.Path, handler)
+	}
+	return nil
+}
+
+// claimRoutes records the routes of controller, failing if one of them is
+// already served by another handler. Routes differing only in parameter
+// names conflict too, as the router could never reach the second one.
+// The caller must hold app.mu.
+func (app *Application) claimRoutes(controller interface{}, routes []controllerRoute) error {
+	if app.routeOwners == nil {
+		app.routeOwners = make(map[string]string)
+	}
+
+	controllerName := reflect.TypeOf(controller).Elem().Name()
+	claimed := make(map[string]string)
+	for _, route := range routes {
+		key := route.HTTPMethod + " " + routePattern(route.Path, app.server.Config().CaseSensitive)
+		owner := controllerName + "." + route.method.Name
+		if existing, ok := app.routeOwners[key]; ok {
+			return fmt.Errorf("route %s %s of %s conflicts with %s", route.HTTPMethod, route.Path, owner, existing)
+		}
+		if existing, ok := claimed[key]; ok {
+			return fmt.Errorf("route %s %s of %s conflicts with %s", route.HTTPMethod, route.Path, owner, existing)
+		}
+		claimed[key] = owner
+	}
+
+	for key, owner := range claimed {
+		app.routeOwners[key] = owner
+	}
+	return nil
+}
+
+// routePattern normalizes path the way the router matches it: parameter
+// names and trailing slashes don't matter, and neither does case unless
+// routing is case sensitive.
+func routePattern(path string, caseSensitive bool) string {
+	segments := strings.Split(strings.TrimSuffix(path, "/"), "/")
+	for i, segment := range segments {
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			segments[i] = ":"
+			if strings.HasSuffix(segment, "?") {
+				segments[i] = ":?"
+			}
+		case !caseSensitive:
+			segments[i] = strings.ToLower(segment)
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// controllerEntry records a registered controller and the prefix it is mounted under.
+type controllerEntry struct {
+	controller interface{}
+	version    string
+	prefix     string
+	module     string
+	middleware []MiddlewareFunc
+	resource   *resource
+	routes     []controllerRoute
+}
+
+// isHandler reports whether name is a handler of the controller: a Handle*
+// method, or a resource action when it is registered as a resource.
+func (e controllerEntry) isHandler(name string) bool {
+	if _, ok := reflect.TypeOf(e.controller).MethodByName(name); !ok {
+		return false
+	}
+	if strings.HasPrefix(name, "Handle") {
+		return true
+	}
+	if e.resource != nil {
+		for _, action := range resourceActions {
+			if action.name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// methodMiddleware returns the per-handler middleware of an optional
+// Middleware() map[string][]MiddlewareFunc method, keyed by handler name.
+func (e controllerEntry) methodMiddleware() (map[string][]MiddlewareFunc, error) {
+	m, ok := e.controller.(interface {
+		Middleware() map[string][]MiddlewareFunc
+	})
+	if !ok {
+		return nil, nil
+	}
+
+	controllerType := reflect.TypeOf(e.controller)
+	middleware := m.Middleware()
+	for handler := range middleware {
+		if !e.isHandler(handler) {
+			return nil, fmt.Errorf("%s.Middleware: unknown handler %q", controllerType.Elem().Name(), handler)
+		}
+	}
+	return middleware, nil
+}
+
+type controllerRoute struct {
+	RouteInfo
+	method    reflect.Method
+	meta      *RouteMetadata
+	websocket bool
+}
+
+// resolveRoutes derives the routes of the controller's Handle* methods from
+// their names, applying the overrides of an optional Routes() method.
+func (e controllerEntry) resolveRoutes() ([]controllerRoute, error) {
+	controllerType := reflect.TypeOf(e.controller)
 
-	
 	controllerName := controllerType.Elem().Name()
-	controllerBaseName := strings.TrimSuffix(controllerName, "Controller")
-	basePath := "/" + strings.ToLower(controllerBaseName)
+	controllerBaseName := strings.ToLower(strings.TrimSuffix(controllerName, "Controller"))
+	basePath := joinPath(e.prefix, controllerBaseName)
+	if e.resource != nil {
+		basePath = joinPath(e.prefix, e.resource.path)
+	}
+
+	overrides := make(map[string][]RouteMetadata)
+	if r, ok := e.controller.(interface{ Routes() []RouteMetadata }); ok {
+		for _, meta := range r.Routes() {
+			if !e.isHandler(meta.Handler) {
+				return nil, fmt.Errorf("%s.Routes: unknown handler %q", controllerName, meta.Handler)
+			}
+			if meta.Method != "" && !isHTTPMethod(meta.Method) {
+				return nil, fmt.Errorf("%s.Routes: invalid method %q for %s", controllerName, meta.Method, meta.Handler)
+			}
+			overrides[meta.Handler] = append(overrides[meta.Handler], meta)
+		}
+	}
 
+	var routes []controllerRoute
+	if e.resource != nil {
+		actions, err := e.resource.routes(controllerType, e.prefix)
+		if err != nil {
+			return nil, err
+		}
+		for _, action := range actions {
+			overridden, err := e.overrideRoute(controllerName, action, overrides[action.method.Name])
+			if err != nil {
+				return nil, err
+			}
+			// Update serves PUT and PATCH; overriding its method yields the
+			// same route twice.
+			for _, route := range overridden {
+				if !hasRoute(routes, route) {
+					routes = append(routes, route)
+				}
+			}
+		}
+	}
 	for i := 0; i < controllerType.NumMethod(); i++ {
 		method := controllerType.Method(i)
 
-		
 		if !strings.HasPrefix(method.Name, "Handle") {
 			continue
 		}
 
-		
-		routeInfo := parseRouteFromMethodName(method.Name, basePath)
+		// HandleWs<Name> serves WebSocket connections on the GET route
+		// HandleGet<Name> would have.
+		websocket := isWebSocketHandler(method)
+		var route RouteInfo
+		if websocket {
+			if err := checkWebSocketHandler(method); err != nil {
+				return nil, fmt.Errorf("%s.%w", controllerName, err)
+			}
+			route = parseRouteFromMethodName("HandleGet"+strings.TrimPrefix(method.Name, "HandleWs"), basePath, controllerBaseName)
+		} else {
+			if _, err := parseHandlerSignature(method); err != nil {
+				return nil, fmt.Errorf("%s.%w", controllerName, err)
+			}
+			route = parseRouteFromMethodName(method.Name, basePath, controllerBaseName)
+		}
+
+		overridden, err := e.overrideRoute(controllerName, controllerRoute{RouteInfo: route, method: method, websocket: websocket}, overrides[method.Name])
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, overridden...)
+	}
+	return routes, nil
+}
+
+// overrideRoute returns the routes serving a handler whose derived route is
+// route, one per entry of its Routes() overrides, or route itself if it has
+// none.
+func (e controllerEntry) overrideRoute(controllerName string, route controllerRoute, metas []RouteMetadata) ([]controllerRoute, error) {
+	if len(metas) == 0 {
+		return []controllerRoute{route}, nil
+	}
 
-		
-		handler := createHandlerFunc(method, controllerValue)
+	routes := make([]controllerRoute, 0, len(metas))
+	for _, meta := range metas {
+		meta := meta
+		overridden := route
+		if meta.Method != "" {
+			if route.websocket && !strings.EqualFold(meta.Method, "GET") {
+				return nil, fmt.Errorf("%s.Routes: WebSocket handler %s must use GET", controllerName, meta.Handler)
+			}
+			overridden.HTTPMethod = strings.ToUpper(meta.Method)
+		}
+		if meta.Path != "" {
+			overridden.Path = joinPath(e.prefix, meta.Path)
+		}
+		overridden.meta = &meta
+		routes = append(routes, overridden)
+	}
+	return routes, nil
+}
 
-		// Route is Registered with the fiber app
-		switch routeInfo.HTTPMethod {
-		case "GET":
-			app.server.Get(routeInfo.Path, handler)
-		case "POST":
-			app.server.Post(routeInfo.Path, handler)
-		case "PUT":
-			app.server.Put(routeInfo.Path, handler)
-		case "DELETE":
-			app.server.Delete(routeInfo.Path, handler)
-		case "PATCH":
-			app.server.Patch(routeInfo.Path, handler)
-		case "OPTIONS":
-			app.server.Options(routeInfo.Path, handler)
-		case "HEAD":
-			app.server.Head(routeInfo.Path, handler)
+func hasRoute(routes []controllerRoute, route controllerRoute) bool {
+	for _, r := range routes {
+		if r.HTTPMethod == route.HTTPMethod && r.Path == route.Path {
+			return true
+		}
+	}
+	return false
+}
+
+var httpMethods = []string{"Get", "Post", "Put", "Delete", "Patch", "Options", "Head"}
+
+func isHTTPMethod(method string) bool {
+	for _, m := range httpMethods {
+		if strings.EqualFold(m, method) {
+			return true
 		}
 	}
+	return false
 }
 
 
@@ -293,8 +737,15 @@ type RouteInfo struct {
 	Path       string
 }
 
-
-func parseRouteFromMethodName(methodName string, basePath string) RouteInfo {
+// parseRouteFromMethodName maps Handle<Method><Action> to a route below
+// basePath, with the action in kebab case. A By<Param>And<Param> suffix adds
+// path parameters; the action is then left out when it names the
+// controller's resource. Parameters of the controller's resource, like
+// userId in a UserController, come before the action so routes nest:
+//
+//	HandleGetUserById                 GET /user/:id
+//	HandleGetPostsByUserIdAndPostId   GET /user/:userId/posts/:postId
+func parseRouteFromMethodName(methodName string, basePath string, resource string) RouteInfo {
 	
 	actionName := strings.TrimPrefix(methodName, "Handle")
 
@@ -302,7 +753,7 @@ func parseRouteFromMethodName(methodName string, basePath string) RouteInfo {
 	httpMethod := "GET"
 
 	
-	for _, method := range []string{"Get", "Post", "Put", "Delete", "Patch", "Options", "Head"} {
+	for _, method := range httpMethods {
 		if strings.HasPrefix(actionName, method) {
 			httpMethod = strings.ToUpper(method)
 			actionName = strings.TrimPrefix(actionName, method)
@@ -310,108 +761,191 @@ func parseRouteFromMethodName(methodName string, basePath string) RouteInfo {
 		}
 	}
 
-	
-	if actionName != "" {
-		
-		var path strings.Builder
-		for i, r := range actionName {
-			if i > 0 && r >= 'A' && r <= 'Z' {
-				path.WriteRune('-')
-			}
-			path.WriteRune(unicode.ToLower(r))
-		}
-
-		actionPath := path.String()
-
-		
-		if actionPath == "index" || actionPath == "" {
-			return RouteInfo{
-				HTTPMethod: httpMethod,
-				Path:       basePath,
-			}
-		}
-
-		// Special case for "By" patterns like GetUserById -> /users/:id
-		if strings.Contains(actionPath, "by-id") {
-			return RouteInfo{
-				HTTPMethod: httpMethod,
-				Path:       fmt.Sprintf("%s/:id", basePath),
-			}
+	words := splitCamelCase(actionName)
+	var params []string
+	for i, word := range words {
+		if word == "By" && i+1 < len(words) {
+			params = routeParams(words[i+1:])
+			words = words[:i]
+			break
 		}
+	}
 
-		return RouteInfo{
-			HTTPMethod: httpMethod,
-			Path:       fmt.Sprintf("%s/%s", basePath, actionPath),
+	actionPath := strings.ToLower(strings.Join(words, "-"))
+	showAction := actionPath != "" && actionPath != "index" &&
+		(len(params) == 0 || !namesResource(strings.ReplaceAll(actionPath, "-", ""), resource))
+
+	path := basePath
+	var actionParams []string
+	for _, param := range params {
+		if showAction && namesResource(paramResource(param), resource) {
+			path = fmt.Sprintf("%s/:%s", path, param)
+		} else {
+			actionParams = append(actionParams, param)
 		}
 	}
+	if showAction {
+		path = fmt.Sprintf("%s/%s", path, actionPath)
+	}
+	for _, param := range actionParams {
+		path = fmt.Sprintf("%s/:%s", path, param)
+	}
 
 	return RouteInfo{
 		HTTPMethod: httpMethod,
-		Path:       basePath,
+		Path:       path,
 	}
 }
 
+// namesResource reports whether name is resource in singular or plural.
+func namesResource(name, resource string) bool {
+	return name != "" && (name == resource || name == resource+"s" || name+"s" == resource)
+}
+
+// paramResource returns the resource a parameter identifies, e.g. "user" for
+// userId, or "" for parameters like id or slug.
+func paramResource(param string) string {
+	name := strings.TrimSuffix(strings.TrimSuffix(param, "Id"), "ID")
+	if name == param {
+		return ""
+	}
+	return strings.ToLower(name)
+}
 
-func createHandlerFunc(method reflect.Method, controllerValue reflect.Value) fiber.Handler {
-	return func(c *fiber.Ctx) error {
-		ctx := &Context{Ctx: c}
-		result := method.Func.Call([]reflect.Value{controllerValue, reflect.ValueOf(ctx)})
-		if len(result) > 0 && !result[0].IsNil() {
-			if err, ok := result[0].Interface().(error); ok {
-				return err
-			}
+// routeParams turns the words after By, e.g. User Id And Post Id, into
+// parameter names: userId, postId.
+func routeParams(words []string) []string {
+	var params []string
+	var current string
+	flush := func() {
+		if current == "" {
+			return
 		}
-		return nil
+		if strings.ToUpper(current) == current {
+			params = append(params, strings.ToLower(current))
+		} else {
+			params = append(params, strings.ToLower(current[:1])+current[1:])
+		}
+		current = ""
+	}
+	for _, word := range words {
+		if word == "And" {
+			flush()
+			continue
+		}
+		current += word
 	}
+	flush()
+	return params
 }
 
 
-func (app *Application) Start() error {
-	if app.queue != nil {
-		app.queue.Start()
-	}
+func (app *Application) createHandlerFunc(method reflect.Method, controllerValue reflect.Value, requestFields []injectField, middleware []MiddlewareFunc) fiber.Handler {
+	// The signature was checked when the routes were resolved.
+	sig, _ := parseHandlerSignature(method)
 
-	return app.server.Listen(fmt.Sprintf("%s:%d", app.config.Server.Host, app.config.Server.Port))
-}
+	handler := func(ctx *Context) error {
+		receiver := controllerValue
+		if len(requestFields) > 0 {
+			scoped, err := app.withRequestFields(controllerValue, requestFields, ctx)
+			if err != nil {
+				return err
+			}
+			receiver = scoped
+		}
 
+		args := []reflect.Value{receiver, reflect.ValueOf(ctx)}
+		if sig.request != nil {
+			request, err := bindRequest(ctx, sig.request)
+			if err != nil {
+				return err
+			}
+			args = append(args, request)
+		}
 
-func (app *Application) Listen(addr string) error {
-	if app.queue != nil {
-		app.queue.Start()
+		result := method.Func.Call(args)
+		if err, ok := result[len(result)-1].Interface().(error); ok && err != nil {
+			return err
+		}
+		if sig.response != nil {
+			return writeResult(ctx, result[0])
+		}
+		return nil
 	}
 
-	return app.server.Listen(addr)
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return func(c *fiber.Ctx) error {
+		return handler(app.context(c))
+	}
 }
 
-// Serve is an alias for Start to provide a more familiar API to users coming from net/http
-func (app *Application) Serve() error {
-	return app.Start()
-}
 
-func (app *Application) Shutdown() error {
-	if app.queue != nil {
-		app.queue.Stop()
+func (app *Application) Start() error {
+	if app.config.Server.Address != "" {
+		return app.Listen(app.config.Server.Address)
 	}
+	return app.Listen(fmt.Sprintf("%s:%d", app.config.Server.Host, app.config.Server.Port))
+}
 
-	if app.plugins != nil {
-		if err := app.plugins.UnloadPlugins(); err != nil {
-			return fmt.Errorf("failed to unload plugins: %w", err)
+// Listen serves on addr, which is host:port, unix:///path/to.sock or
+// systemd[:name]. See Serve to use an existing listener.
+func (app *Application) Listen(addr string) error {
+	return app.run(func() error {
+		if app.prefork(addr) {
+			return app.server.Listen(addr)
 		}
-	}
 
-	if app.database != nil {
-		if err := app.database.Close(); err != nil {
-			return fmt.Errorf("failed to close database: %w", err)
+		ln, err := app.listen(addr)
+		if err != nil {
+			return err
 		}
-	}
+		return app.serve(ln)
+	})
+}
 
-	return app.server.Shutdown()
+// Shutdown gracefully stops the application, bounded by Server.ShutdownTimeout.
+func (app *Application) Shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), app.shutdownTimeout())
+	defer cancel()
+	return app.ShutdownWithContext(ctx)
 }
 
 func (app *Application) DB() *gorm.DB {
 	return app.database.DB
 }
 
+// DBNamed returns the named connection from Config.Databases, or nil if it
+// is not configured.
+func (app *Application) DBNamed(name string) *gorm.DB {
+	if db := app.DatabaseNamed(name); db != nil {
+		return db.DB
+	}
+	return nil
+}
+
+func (app *Application) DatabaseNamed(name string) *Database {
+	return app.databases[name]
+}
+
+// Migrator returns a migration manager for the named connection. Migrations
+// always run against the primary, never a replica. The default connection's
+// manager starts with the migrations of every mounted module.
+func (app *Application) Migrator(name string) (*MigrationManager, error) {
+	db := app.DatabaseNamed(name)
+	if db == nil {
+		return nil, fmt.Errorf("database connection %q is not configured", name)
+	}
+	migrator := NewMigrationManager(db)
+	if name == DefaultDatabase {
+		app.mu.RLock()
+		migrator.Migrations = append(migrator.Migrations, app.migrations...)
+		app.mu.RUnlock()
+	}
+	return migrator, nil
+}
+
 func (app *Application) Auth() *auth.JWTManager {
 	return app.auth.JWTManager
 }
@@ -436,14 +970,6 @@ func (app *Application) WithLogField(key string, value interface{}) *logger.Logg
 	return app.logger.WithField(key, value)
 }
 
-func (a *Application) Group(prefix string) fiber.Router {
-	return a.server.Group(prefix)
-}
-
-func (a *Application) Use(middleware ...interface{}) {
-	a.server.Use(middleware...)
-}
-
 func (a *Application) Get() *fiber.App {
 	return a.server
 }
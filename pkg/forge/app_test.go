@@ -0,0 +1,87 @@
+package forge
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestApp creates an Application inside a temporary working directory so
+// the plugin directory created by New does not leak into the source tree.
+func newTestApp(t *testing.T, config *Config) *Application {
+	t.Helper()
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() {
+		os.Chdir(wd)
+	})
+
+	if config.Name == "" {
+		config.Name = "Forge Test"
+	}
+	if config.CORS.AllowOrigins == "" {
+		config.CORS = CORSConfig{AllowOrigins: "http://localhost", AllowMethods: "GET,POST,PUT,DELETE,PATCH"}
+	}
+
+	app, err := New(config)
+	require.NoError(t, err)
+	return app
+}
+
+func TestServerConfigIsPassedToFiber(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	config := app.server.Config()
+	assert.Equal(t, DefaultReadTimeout, config.ReadTimeout)
+	assert.Equal(t, DefaultIdleTimeout, config.IdleTimeout)
+	assert.Zero(t, config.WriteTimeout)
+	assert.False(t, config.EnableTrustedProxyCheck)
+
+	app = newTestApp(t, &Config{
+		Server: ServerConfig{
+			ReadTimeout:    5 * time.Second,
+			WriteTimeout:   15 * time.Second,
+			IdleTimeout:    time.Minute,
+			BodyLimit:      1 << 20,
+			MaxHeaderBytes: 8192,
+			Concurrency:    1024,
+			TrustedProxies: []string{"10.0.0.0/8"},
+			ProxyHeader:    fiber.HeaderXForwardedFor,
+		},
+	})
+	config = app.server.Config()
+	assert.Equal(t, 5*time.Second, config.ReadTimeout)
+	assert.Equal(t, 15*time.Second, config.WriteTimeout)
+	assert.Equal(t, time.Minute, config.IdleTimeout)
+	assert.Equal(t, 1<<20, config.BodyLimit)
+	assert.Equal(t, 8192, config.ReadBufferSize)
+	assert.Equal(t, 1024, config.Concurrency)
+	assert.True(t, config.EnableTrustedProxyCheck)
+	assert.Equal(t, []string{"10.0.0.0/8"}, config.TrustedProxies)
+	assert.Equal(t, "X-Forwarded-For", config.ProxyHeader)
+}
+
+func TestBodyLimitRejectsLargeRequests(t *testing.T) {
+	app := newTestApp(t, &Config{Server: ServerConfig{BodyLimit: 16}})
+	app.Get().Post("/echo", func(c *fiber.Ctx) error {
+		return c.Send(c.Body())
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader("small"))
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// fasthttp rejects the oversized body while reading the request
+	req = httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(strings.Repeat("x", 64)))
+	_, err = app.Test(req)
+	assert.ErrorContains(t, err, "body size exceeds")
+}
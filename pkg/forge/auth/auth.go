@@ -23,7 +23,7 @@ type Auth struct {
 }
 
 type Config struct {
-	SecretKey     string        `yaml:"secret_key"`
+	SecretKey     string        `yaml:"secret_key" secret:"true"`
 	TokenDuration time.Duration `yaml:"token_duration"`
 }
 
@@ -0,0 +1,289 @@
+package forge
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// handlerSignature describes a Handle* method. Besides func(*Context) error,
+// handlers may take a pointer to a request struct and return a result:
+//
+//	func(*Context, *CreateUserRequest) (*UserResponse, error)
+//	func(*Context, *CreateUserRequest) error
+//	func(*Context) (*UserResponse, error)
+type handlerSignature struct {
+	request  reflect.Type // struct type, nil without a request argument
+	response reflect.Type // nil without a result
+}
+
+// parseHandlerSignature checks the signature of method, whose first argument
+// is the receiver.
+func parseHandlerSignature(method reflect.Method) (handlerSignature, error) {
+	var sig handlerSignature
+	t := method.Type
+
+	if t.NumIn() < 2 || t.NumIn() > 3 || t.In(1) != contextType {
+		return sig, fmt.Errorf("%s must take *forge.Context and optionally a request struct pointer", method.Name)
+	}
+	if t.NumIn() == 3 {
+		if t.In(2).Kind() != reflect.Ptr || t.In(2).Elem().Kind() != reflect.Struct {
+			return sig, fmt.Errorf("%s: request argument must be a pointer to a struct, not %s", method.Name, t.In(2))
+		}
+		sig.request = t.In(2).Elem()
+	}
+
+	switch {
+	case t.NumOut() == 1 && t.Out(0) == errorType:
+	case t.NumOut() == 2 && t.Out(1) == errorType:
+		sig.response = t.Out(0)
+	default:
+		return sig, fmt.Errorf("%s must return error or (result, error)", method.Name)
+	}
+	return sig, nil
+}
+
+// ResultStatus lets a handler result choose its response status.
+type ResultStatus interface {
+	StatusCode() int
+}
+
+// writeResult renders the result of a typed handler in the media type the
+// client accepts. The status is the one set with ctx.Status, else the
+// result's StatusCode(), else 201 for POST, 204 for a nil result and 200
+// otherwise.
+func writeResult(ctx *Context, result reflect.Value) error {
+	status := ctx.Response().StatusCode()
+	if status == http.StatusOK {
+		switch {
+		case isNilValue(result):
+			status = http.StatusNoContent
+		case result.Type().Implements(reflect.TypeOf((*ResultStatus)(nil)).Elem()):
+			status = result.Interface().(ResultStatus).StatusCode()
+		case ctx.Method() == http.MethodPost:
+			status = http.StatusCreated
+		}
+	}
+
+	if isNilValue(result) {
+		return ctx.SendStatus(status)
+	}
+	return ctx.Render(status, result.Interface())
+}
+
+func isNilValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		return v.IsNil()
+	}
+	return false
+}
+
+// bindRequest creates a request of type t from the body and the fields
+// tagged path, query and header, then validates it. Malformed input and
+// failed validation are returned as a ValidationError.
+func bindRequest(ctx *Context, t reflect.Type) (reflect.Value, error) {
+	request := reflect.New(t)
+
+	if len(ctx.Body()) > 0 {
+		if err := ctx.Bind(request.Interface()); err != nil {
+			return request, ValidationError(map[string]string{"body": bodyErrorMessage(ctx, err)})
+		}
+	}
+
+	invalid := make(map[string]string)
+	bindTagged(ctx, request.Elem(), invalid)
+	if len(invalid) > 0 {
+		return request, ValidationError(invalid)
+	}
+
+	if err := ctx.Validate(request.Interface()); err != nil {
+		var fieldErrors validator.ValidationErrors
+		if !errors.As(err, &fieldErrors) {
+			return request, err
+		}
+		for _, fieldErr := range fieldErrors {
+			invalid[requestFieldName(t, fieldErr)] = validationMessage(fieldErr)
+		}
+		return request, ValidationError(invalid)
+	}
+	return request, nil
+}
+
+func bodyErrorMessage(ctx *Context, err error) string {
+	if errors.Is(err, fiber.ErrUnprocessableEntity) {
+		return fmt.Sprintf("unsupported content type %q", ctx.Get(fiber.HeaderContentType))
+	}
+	return "malformed request body: " + err.Error()
+}
+
+// bindTagged fills the fields tagged path, query or header, recording values
+// that don't convert in invalid.
+func bindTagged(ctx *Context, v reflect.Value, invalid map[string]string) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			bindTagged(ctx, v.Field(i), invalid)
+			continue
+		}
+
+		var values []string
+		var name string
+		switch {
+		case field.Tag.Get("path") != "":
+			name = field.Tag.Get("path")
+			if value := ctx.Params(name); value != "" {
+				values = []string{value}
+			}
+		case field.Tag.Get("query") != "":
+			name = field.Tag.Get("query")
+			for _, value := range ctx.Context().QueryArgs().PeekMulti(name) {
+				values = append(values, string(value))
+			}
+		case field.Tag.Get("header") != "":
+			name = field.Tag.Get("header")
+			if value := ctx.Get(name); value != "" {
+				values = []string{value}
+			}
+		default:
+			continue
+		}
+		// Values for these fields only come from their source, never the body.
+		v.Field(i).Set(reflect.Zero(field.Type))
+		if len(values) == 0 {
+			continue
+		}
+
+		if err := setField(v.Field(i), values); err != nil {
+			invalid[name] = err.Error()
+		}
+	}
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func setField(field reflect.Value, values []string) error {
+	if field.Kind() == reflect.Slice {
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := setValue(slice.Index(i), value); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+	if field.Kind() == reflect.Ptr {
+		ptr := reflect.New(field.Type().Elem())
+		if err := setValue(ptr.Elem(), values[0]); err != nil {
+			return err
+		}
+		field.Set(ptr)
+		return nil
+	}
+	return setValue(field, values[0])
+}
+
+func setValue(v reflect.Value, value string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return errors.New("must be a duration")
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.New("must be a boolean")
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return errors.New("must be an integer")
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return errors.New("must be a non-negative integer")
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return errors.New("must be a number")
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+	return nil
+}
+
+// requestFieldName reports a validation failure under the name the client
+// used for the field: its path, query, header or json name.
+func requestFieldName(t reflect.Type, fieldErr validator.FieldError) string {
+	if strings.Count(fieldErr.StructNamespace(), ".") == 1 {
+		if field, ok := t.FieldByName(fieldErr.StructField()); ok {
+			if name := requestTagName(field); name != "" {
+				return name
+			}
+		}
+	}
+	return fieldErr.Field()
+}
+
+// requestTagName returns the name a request field is bound from.
+func requestTagName(field reflect.StructField) string {
+	for _, tag := range []string{"path", "query", "header", "json", "form"} {
+		if name := strings.Split(field.Tag.Get(tag), ",")[0]; name != "" && name != "-" {
+			return name
+		}
+	}
+	return ""
+}
+
+func validationMessage(fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "min", "gte", "max", "lte":
+		bound := "at least "
+		if fieldErr.Tag() == "max" || fieldErr.Tag() == "lte" {
+			bound = "at most "
+		}
+		switch fieldErr.Kind() {
+		case reflect.String:
+			return "must be " + bound + fieldErr.Param() + " characters long"
+		case reflect.Slice, reflect.Map, reflect.Array:
+			return "must have " + bound + fieldErr.Param() + " items"
+		}
+		return "must be " + bound + fieldErr.Param()
+	case "oneof":
+		return "must be one of " + fieldErr.Param()
+	}
+	if fieldErr.Param() != "" {
+		return fmt.Sprintf("must satisfy %s=%s", fieldErr.Tag(), fieldErr.Param())
+	}
+	return "must satisfy " + fieldErr.Tag()
+}
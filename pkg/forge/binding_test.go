@@ -0,0 +1,202 @@
+package forge
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type OrderController struct {
+	Controller
+}
+
+type CreateOrderRequest struct {
+	Item     string `json:"item" validate:"required"`
+	Quantity int    `json:"quantity" validate:"min=1"`
+	Tenant   string `header:"X-Tenant" validate:"required"`
+	DryRun   bool   `query:"dry_run"`
+}
+
+type UpdateOrderRequest struct {
+	ID   int      `path:"id"`
+	Tags []string `query:"tag" validate:"max=2"`
+	Note string   `json:"note"`
+}
+
+type OrderResponse struct {
+	ID       int      `json:"id"`
+	Item     string   `json:"item,omitempty"`
+	Quantity int      `json:"quantity,omitempty"`
+	Tenant   string   `json:"tenant,omitempty"`
+	DryRun   bool     `json:"dry_run,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Note     string   `json:"note,omitempty"`
+}
+
+func (r *OrderResponse) StatusCode() int {
+	return http.StatusAccepted
+}
+
+func (c *OrderController) HandlePostIndex(ctx *Context, req *CreateOrderRequest) (*OrderResponse, error) {
+	return &OrderResponse{ID: 1, Item: req.Item, Quantity: req.Quantity, Tenant: req.Tenant, DryRun: req.DryRun}, nil
+}
+
+func (c *OrderController) HandlePutById(ctx *Context, req *UpdateOrderRequest) (*OrderResponse, error) {
+	return &OrderResponse{ID: req.ID, Tags: req.Tags, Note: req.Note}, nil
+}
+
+func (c *OrderController) HandleGetById(ctx *Context) (map[string]int, error) {
+	if ctx.Param("id") == "0" {
+		return nil, NotFoundError("order")
+	}
+	return map[string]int{"id": 7}, nil
+}
+
+func (c *OrderController) HandleDeleteById(ctx *Context) (map[string]int, error) {
+	return nil, nil
+}
+
+type InvalidSignatureController struct {
+	Controller
+}
+
+func (c *InvalidSignatureController) HandleGetIndex(ctx *Context, req CreateOrderRequest) error {
+	return nil
+}
+
+func sendOrder(t *testing.T, app *Application, method, path, body string, header map[string]string) (int, map[string]interface{}) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	var decoded map[string]interface{}
+	if len(data) > 0 {
+		require.NoError(t, json.Unmarshal(data, &decoded), string(data))
+	}
+	return resp.StatusCode, decoded
+}
+
+func TestTypedHandlerBindsRequest(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	require.NoError(t, app.RegisterController(&OrderController{}))
+
+	status, body := sendOrder(t, app, http.MethodPost, "/order?dry_run=true", `{"item":"book","quantity":2}`, map[string]string{"X-Tenant": "acme"})
+	assert.Equal(t, http.StatusAccepted, status, "StatusCode() chooses the status")
+	assert.Equal(t, map[string]interface{}{"id": 1.0, "item": "book", "quantity": 2.0, "tenant": "acme", "dry_run": true}, body)
+
+	status, body = sendOrder(t, app, http.MethodPut, "/order/42?tag=a&tag=b", `{"note":"gift"}`, nil)
+	assert.Equal(t, http.StatusAccepted, status)
+	assert.Equal(t, map[string]interface{}{"id": 42.0, "tags": []interface{}{"a", "b"}, "note": "gift"}, body)
+}
+
+func TestTypedHandlerResultStatus(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	require.NoError(t, app.RegisterController(&OrderController{}))
+
+	status, body := sendOrder(t, app, http.MethodGet, "/order/7", "", nil)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, map[string]interface{}{"id": 7.0}, body)
+
+	status, _ = sendOrder(t, app, http.MethodGet, "/order/0", "", nil)
+	assert.Equal(t, http.StatusNotFound, status, "returned errors go to the error handler")
+
+	status, body = sendOrder(t, app, http.MethodDelete, "/order/7", "", nil)
+	assert.Equal(t, http.StatusNoContent, status)
+	assert.Nil(t, body)
+}
+
+func TestTypedHandlerValidationErrors(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	require.NoError(t, app.RegisterController(&OrderController{}))
+
+	status, body := sendOrder(t, app, http.MethodPost, "/order", `{"quantity":0}`, nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, map[string]interface{}{
+		"item":     "is required",
+		"quantity": "must be at least 1",
+		"X-Tenant": "is required",
+	}, body["details"])
+
+	status, body = sendOrder(t, app, http.MethodPost, "/order", `{"item":"book","quantity":1,"Tenant":"acme"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, map[string]interface{}{"X-Tenant": "is required"}, body["details"], "header fields are not read from the body")
+
+	status, body = sendOrder(t, app, http.MethodPost, "/order?dry_run=maybe", `{"item":"book","quantity":1}`, map[string]string{"X-Tenant": "acme"})
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, map[string]interface{}{"dry_run": "must be a boolean"}, body["details"])
+
+	status, body = sendOrder(t, app, http.MethodPut, "/order/abc", "", nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, map[string]interface{}{"id": "must be an integer"}, body["details"])
+
+	status, body = sendOrder(t, app, http.MethodPut, "/order/1?tag=a&tag=b&tag=c", "", nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, map[string]interface{}{"tag": "must have at most 2 items"}, body["details"])
+
+	status, body = sendOrder(t, app, http.MethodPost, "/order", `{"item":`, map[string]string{"X-Tenant": "acme"})
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Contains(t, body["details"], "body")
+}
+
+func TestRegisterControllerRejectsInvalidSignatures(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	err := app.RegisterController(&InvalidSignatureController{})
+	assert.ErrorContains(t, err, "InvalidSignatureController.HandleGetIndex: request argument must be a pointer to a struct")
+}
+
+func TestTypedHandlerOpenAPI(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	require.NoError(t, app.RegisterController(&OrderController{}))
+
+	spec, err := app.GenerateOpenAPI()
+	require.NoError(t, err)
+
+	create := spec.Paths["/order"].Post
+	require.NotNil(t, create)
+	require.Len(t, create.Parameters, 2)
+	assert.Equal(t, Parameter{Name: "X-Tenant", In: "header", Required: true, Schema: &Schema{Type: "string"}}, *create.Parameters[0])
+	assert.Equal(t, Parameter{Name: "dry_run", In: "query", Schema: &Schema{Type: "boolean"}}, *create.Parameters[1])
+
+	require.NotNil(t, create.RequestBody)
+	body := create.RequestBody.Content["application/json"].Schema
+	assert.ElementsMatch(t, []string{"item", "quantity"}, keys(body.Properties), "bound parameters are not part of the body")
+
+	assert.NotContains(t, create.Responses, "200")
+	require.Contains(t, create.Responses, "201")
+	assert.Contains(t, create.Responses["201"].Content["application/json"].Schema.Properties, "tenant")
+	assert.Contains(t, create.Responses, "400")
+
+	update := spec.Paths["/order/{id}"].Put
+	require.NotNil(t, update)
+	require.Len(t, update.Parameters, 2, "the path parameter is documented once")
+	assert.Equal(t, Parameter{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "integer"}}, *update.Parameters[0])
+	assert.Equal(t, "array", update.Parameters[1].Schema.Type)
+
+	get := spec.Paths["/order/{id}"].Get
+	require.NotNil(t, get)
+	assert.NotContains(t, get.Responses, "400", "no request, no validation error")
+	assert.Equal(t, "object", get.Responses["200"].Content["application/json"].Schema.Type)
+}
+
+func keys(m map[string]*Schema) []string {
+	var names []string
+	for name := range m {
+		names = append(names, name)
+	}
+	return names
+}
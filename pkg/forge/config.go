@@ -0,0 +1,575 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BisiOlaYemi/forge/pkg/forge/mailer"
+	"github.com/BisiOlaYemi/forge/pkg/forge/queue"
+	"github.com/BisiOlaYemi/forge/pkg/forge/secrets"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm/logger"
+)
+
+// envPrefix is prepended to every environment variable that overrides a config value.
+const envPrefix = "FORGE"
+
+const (
+	EnvDevelopment = "development"
+	EnvTest        = "test"
+	EnvProduction  = "production"
+)
+
+// ViewConfig mirrors the view section of forge.yaml
+type ViewConfig struct {
+	Engine    string `yaml:"engine"`
+	Directory string `yaml:"directory"`
+	Extension string `yaml:"extension"`
+	// Views are parsed once and cached, except in development or with
+	// Reload set, where they are re-parsed when their files change.
+	Reload bool   `yaml:"reload"`
+	Layout string `yaml:"layout"` // default layout, e.g. layouts/main
+	Assets string `yaml:"assets"` // URL prefix of static assets, defaults to /assets
+}
+
+// configFile is the on-disk layout written by `forge new` to config/forge.yaml.
+type configFile struct {
+	App      appFile       `yaml:"app"`
+	Server   ServerConfig  `yaml:"server"`
+	Database databaseFiles `yaml:"database"`
+	Auth     struct {
+		JWT jwtFile `yaml:"jwt"`
+	} `yaml:"auth"`
+	Mailer mailer.Config `yaml:"mailer"`
+	Queue  queue.Config  `yaml:"queue"`
+	CORS   CORSConfig    `yaml:"cors"`
+	View   ViewConfig    `yaml:"view"`
+	Health HealthConfig  `yaml:"health"`
+
+	WebSocket WebSocketConfig `yaml:"websocket"`
+	SSE       SSEConfig       `yaml:"sse"`
+	Flags     FlagsConfig     `yaml:"flags"`
+
+	AdminServer AdminServerConfig `yaml:"admin_server"`
+
+	Modules map[string]interface{} `yaml:"modules"`
+
+	secrets secrets.Provider
+}
+
+type appFile struct {
+	Name        string `yaml:"name"`
+	Version     string `yaml:"version"`
+	Description string `yaml:"description"`
+	Environment string `yaml:"environment"`
+	Debug       bool   `yaml:"debug"`
+	LogLevel    string `yaml:"log_level"`
+}
+
+type databaseFile struct {
+	DatabaseConfig `yaml:",inline"`
+	LogLevel       string `yaml:"log_level"`
+}
+
+// databaseFiles holds the named connections under the database section.
+type databaseFiles map[string]databaseFile
+
+// UnmarshalYAML merges each connection into the one already loaded, so a
+// profile can override a single field of database.default.
+func (m *databaseFiles) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: database must be a map of named connections", node.Line)
+	}
+	if *m == nil {
+		*m = make(databaseFiles)
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		name := node.Content[i].Value
+		db := (*m)[name]
+		if err := node.Content[i+1].Decode(&db); err != nil {
+			return err
+		}
+		(*m)[name] = db
+	}
+	return nil
+}
+
+type jwtFile struct {
+	SecretKey  string  `yaml:"secret_key" secret:"true"`
+	Expiration seconds `yaml:"expiration"`
+}
+
+// seconds is a number of seconds that may also be written as a duration,
+// such as 86400s or 24h.
+type seconds int
+
+func (s *seconds) UnmarshalYAML(node *yaml.Node) error {
+	n, err := parseSeconds(node.Value)
+	if err != nil || node.Kind != yaml.ScalarNode {
+		return fmt.Errorf("line %d: %q is neither a number of seconds nor a duration", node.Line, node.Value)
+	}
+	*s = n
+	return nil
+}
+
+// parseSeconds reads a number of seconds, such as 86400, or a duration,
+// such as 24h.
+func parseSeconds(raw string) (seconds, error) {
+	if n, err := strconv.Atoi(raw); err == nil {
+		return seconds(n), nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("%q is neither a number of seconds nor a duration", raw)
+	}
+	return seconds(d / time.Second), nil
+}
+
+// LoadOption customises how LoadConfig resolves configuration.
+type LoadOption func(*loadOptions)
+
+type loadOptions struct {
+	environment string
+	secrets     secrets.Provider
+}
+
+// WithEnvironment selects the environment profile, taking precedence over FORGE_ENV.
+func WithEnvironment(env string) LoadOption {
+	return func(o *loadOptions) {
+		o.environment = env
+	}
+}
+
+// WithSecretProvider sets where ${KEY} references in config files are
+// looked up. It defaults to secrets.Default: environment variables, then
+// files in /run/secrets.
+func WithSecretProvider(provider secrets.Provider) LoadOption {
+	return func(o *loadOptions) {
+		o.secrets = provider
+	}
+}
+
+// LoadConfig reads a forge.yaml file together with its environment profiles.
+//
+// Layers are applied in order, each overriding the previous one:
+//
+//	forge.yaml          base configuration
+//	forge.<env>.yaml    environment profile, e.g. forge.production.yaml
+//	forge.local.yaml    untracked developer overrides
+//	FORGE_* variables   environment overrides
+//
+// The active environment comes from WithEnvironment, then FORGE_ENV, then
+// app.environment in the base file, and defaults to development.
+//
+// Environment variable names are derived from the yaml path of each value,
+// e.g. server.port becomes FORGE_SERVER_PORT and database.default.password
+// becomes FORGE_DATABASE_DEFAULT_PASSWORD. Map entries, such as named
+// database connections, can only be overridden once a file declares them.
+//
+// Secrets can be kept out of the files: any value may reference a secret as
+// ${KEY} or ${KEY:-default}, and fields tagged `secret:"true"` can instead be
+// read from a file with a _file suffix, e.g. password_file: /run/secrets/db,
+// or FORGE_DATABASE_DEFAULT_PASSWORD_FILE.
+func LoadConfig(path string, opts ...LoadOption) (*Config, error) {
+	options := &loadOptions{secrets: secrets.Default()}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	file := configFile{secrets: options.secrets}
+	if err := file.merge(path, true); err != nil {
+		return nil, err
+	}
+
+	env := options.environment
+	if env == "" {
+		env = os.Getenv(envPrefix + "_ENV")
+	}
+	if env == "" {
+		env = file.App.Environment
+	}
+	if env == "" {
+		env = EnvDevelopment
+	}
+
+	dir := filepath.Dir(path)
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(filepath.Base(path), ext)
+	for _, layer := range []string{env, "local"} {
+		layerPath := filepath.Join(dir, base+"."+layer+ext)
+		if err := file.merge(layerPath, false); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := applyEnvOverrides(reflect.ValueOf(&file).Elem(), envPrefix); err != nil {
+		return nil, err
+	}
+	file.App.Environment = env
+
+	return file.toConfig()
+}
+
+// NewFromFile loads the config at path and creates an Application from it.
+func NewFromFile(path string, opts ...LoadOption) (*Application, error) {
+	config, err := LoadConfig(path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return New(config)
+}
+
+// merge decodes the file at path on top of the values already in f.
+func (f *configFile) merge(path string, required bool) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !required && os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	if node.Kind == 0 {
+		return nil
+	}
+	if err := resolveSecrets(&node, reflect.TypeOf(f).Elem(), f.secrets); err != nil {
+		return fmt.Errorf("failed to resolve secrets in %s: %w", path, err)
+	}
+	if err := node.Decode(f); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// resolveSecrets walks a parsed config file alongside the type it decodes
+// into. It expands ${KEY} references in scalar values and replaces
+// <field>_file keys of secret fields with the contents of the named file.
+func resolveSecrets(node *yaml.Node, t reflect.Type, provider secrets.Provider) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			if err := resolveSecrets(child, t, provider); err != nil {
+				return err
+			}
+		}
+
+	case yaml.ScalarNode:
+		if !strings.Contains(node.Value, "${") {
+			return nil
+		}
+		value, err := secrets.Expand(context.Background(), node.Value, provider)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		node.Value = value
+		// Let the expanded value resolve to the field type, so port: ${PORT}
+		// decodes as an int while a numeric password stays a string.
+		if t.Kind() == reflect.String {
+			node.Tag = "!!str"
+		} else {
+			node.Tag = ""
+			node.Style = 0
+		}
+
+	case yaml.SequenceNode:
+		if t.Kind() != reflect.Slice {
+			return nil
+		}
+		for _, child := range node.Content {
+			if err := resolveSecrets(child, t.Elem(), provider); err != nil {
+				return err
+			}
+		}
+
+	case yaml.MappingNode:
+		switch t.Kind() {
+		case reflect.Map:
+			for i := 1; i < len(node.Content); i += 2 {
+				if err := resolveSecrets(node.Content[i], t.Elem(), provider); err != nil {
+					return err
+				}
+			}
+		case reflect.Struct:
+			fields := yamlFields(t)
+			keys := make(map[string]bool, len(node.Content)/2)
+			for i := 0; i < len(node.Content); i += 2 {
+				keys[node.Content[i].Value] = true
+			}
+
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key, value := node.Content[i], node.Content[i+1]
+				if field, ok := fields[key.Value]; ok {
+					if err := resolveSecrets(value, field.Type, provider); err != nil {
+						return err
+					}
+					continue
+				}
+
+				name := strings.TrimSuffix(key.Value, "_file")
+				field, ok := fields[name]
+				if name == key.Value || !ok || !secrets.IsSecret(field) {
+					continue
+				}
+				if keys[name] {
+					return fmt.Errorf("line %d: %s and %s are both set", key.Line, name, key.Value)
+				}
+				if err := resolveSecrets(value, reflect.TypeOf(""), provider); err != nil {
+					return err
+				}
+				secret, err := secrets.ReadFile(value.Value)
+				if err != nil {
+					return fmt.Errorf("line %d: %s: %w", key.Line, key.Value, err)
+				}
+				key.Value = name
+				value.Value = secret
+				value.Tag = "!!str"
+			}
+		}
+	}
+	return nil
+}
+
+// yamlFields indexes the fields of a struct by yaml name, including the
+// fields of inlined structs.
+func yamlFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		tag := strings.Split(field.Tag.Get("yaml"), ",")
+		if tag[0] == "-" {
+			continue
+		}
+		if field.Anonymous && len(tag) > 1 && tag[1] == "inline" {
+			for name, inner := range yamlFields(field.Type) {
+				fields[name] = inner
+			}
+			continue
+		}
+		name := tag[0]
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		fields[name] = field
+	}
+	return fields
+}
+
+func (f *configFile) toConfig() (*Config, error) {
+	config := &Config{
+		Name:        f.App.Name,
+		Version:     f.App.Version,
+		Description: f.App.Description,
+		Environment: f.App.Environment,
+		Debug:       f.App.Debug,
+		LogLevel:    f.App.LogLevel,
+		Server:      f.Server,
+		Mailer:      f.Mailer,
+		Queue:       f.Queue,
+		CORS:        f.CORS,
+		View:        f.View,
+		WebSocket:   f.WebSocket,
+		SSE:         f.SSE,
+		Health:      f.Health,
+		Flags:       f.Flags,
+		AdminServer: f.AdminServer,
+		Modules:     f.Modules,
+	}
+
+	for name, db := range f.Database {
+		if db.Driver == "" {
+			continue
+		}
+		level, err := parseGormLogLevel(db.LogLevel)
+		if err != nil {
+			return nil, fmt.Errorf("database %s: %w", name, err)
+		}
+		if config.Databases == nil {
+			config.Databases = make(map[string]DatabaseConfig)
+		}
+		db.DatabaseConfig.LogLevel = level
+		config.Databases[name] = db.DatabaseConfig
+	}
+	config.Database = config.Databases[DefaultDatabase]
+
+	config.Auth.SecretKey = f.Auth.JWT.SecretKey
+	config.Auth.TokenDuration = time.Duration(f.Auth.JWT.Expiration) * time.Second
+
+	return config, nil
+}
+
+func parseGormLogLevel(level string) (logger.LogLevel, error) {
+	switch strings.ToLower(level) {
+	case "":
+		return 0, nil
+	case "silent":
+		return logger.Silent, nil
+	case "error":
+		return logger.Error, nil
+	case "warn":
+		return logger.Warn, nil
+	case "info":
+		return logger.Info, nil
+	default:
+		return 0, fmt.Errorf("unsupported database log level: %s", level)
+	}
+}
+
+// applyEnvOverrides walks v by yaml tag and replaces every scalar that has a
+// matching PREFIX_PATH environment variable.
+func applyEnvOverrides(v reflect.Value, prefix string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		tag := strings.Split(field.Tag.Get("yaml"), ",")
+		if tag[0] == "-" {
+			continue
+		}
+
+		fieldValue := v.Field(i)
+		if field.Anonymous && len(tag) > 1 && tag[1] == "inline" {
+			if err := applyEnvOverrides(fieldValue, prefix); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name := tag[0]
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		key := prefix + "_" + strings.ToUpper(name)
+
+		if fieldValue.Kind() == reflect.Struct {
+			if err := applyEnvOverrides(fieldValue, key); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if fieldValue.Kind() == reflect.Map && fieldValue.Type().Elem().Kind() == reflect.Struct {
+			if err := applyMapEnvOverrides(fieldValue, key); err != nil {
+				return err
+			}
+			continue
+		}
+
+		raw, ok := os.LookupEnv(key)
+		if !ok && secrets.IsSecret(field) {
+			if path, set := os.LookupEnv(key + "_FILE"); set {
+				secret, err := secrets.ReadFile(path)
+				if err != nil {
+					return fmt.Errorf("invalid value for %s_FILE: %w", key, err)
+				}
+				raw, ok = secret, true
+			}
+		}
+		if !ok {
+			continue
+		}
+		if err := setFromString(fieldValue, raw); err != nil {
+			return fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// applyMapEnvOverrides applies overrides to every struct entry of a
+// string-keyed map, using the upper-cased key as the next path segment.
+func applyMapEnvOverrides(m reflect.Value, prefix string) error {
+	iter := m.MapRange()
+	for iter.Next() {
+		entry := reflect.New(m.Type().Elem()).Elem()
+		entry.Set(iter.Value())
+
+		name := strings.ToUpper(strings.ReplaceAll(iter.Key().String(), "-", "_"))
+		if err := applyEnvOverrides(entry, prefix+"_"+name); err != nil {
+			return err
+		}
+		m.SetMapIndex(iter.Key(), entry)
+	}
+	return nil
+}
+
+func setFromString(v reflect.Value, raw string) error {
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	if v.Type() == reflect.TypeOf(seconds(0)) {
+		n, err := parseSeconds(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(n))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported slice type %s", v.Type())
+		}
+		parts := strings.Split(raw, ",")
+		slice := reflect.MakeSlice(v.Type(), 0, len(parts))
+		for _, part := range parts {
+			if part = strings.TrimSpace(part); part != "" {
+				slice = reflect.Append(slice, reflect.ValueOf(part))
+			}
+		}
+		v.Set(slice)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,287 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BisiOlaYemi/forge/pkg/forge/auth"
+	"github.com/BisiOlaYemi/forge/pkg/forge/mailer"
+	"github.com/BisiOlaYemi/forge/pkg/forge/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/logger"
+)
+
+const testConfigYAML = `
+app:
+  name: "shop"
+  version: "1.2.0"
+  description: "A shop"
+  environment: "development"
+  debug: true
+  log_level: "debug"
+
+server:
+  host: "localhost"
+  port: 3000
+  base_path: "/"
+
+database:
+  default:
+    driver: "sqlite"
+    name: "shop.db"
+    max_open_conns: 100
+    conn_max_life: 3600s
+    slow_threshold: 200ms
+    log_level: "warn"
+
+auth:
+  jwt:
+    secret_key: "file-secret"
+    expiration: 86400
+
+view:
+  engine: "go-template"
+  directory: "templates"
+  extension: ".gohtml"
+  cache: true
+`
+
+func writeTestConfig(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := writeTestConfig(t, t.TempDir(), "forge.yaml", testConfigYAML)
+
+	config, err := LoadConfig(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "shop", config.Name)
+	assert.Equal(t, "1.2.0", config.Version)
+	assert.Equal(t, "development", config.Environment)
+	assert.True(t, config.Debug)
+	assert.Equal(t, "debug", config.LogLevel)
+	assert.Equal(t, 3000, config.Server.Port)
+	assert.Equal(t, "sqlite", config.Database.Driver)
+	assert.Equal(t, "shop.db", config.Database.Name)
+	assert.Equal(t, time.Hour, config.Database.ConnMaxLife)
+	assert.Equal(t, 200*time.Millisecond, config.Database.SlowThreshold)
+	assert.Equal(t, logger.Warn, config.Database.LogLevel)
+	assert.Equal(t, "file-secret", config.Auth.SecretKey)
+	assert.Equal(t, 24*time.Hour, config.Auth.TokenDuration)
+	assert.Equal(t, "templates", config.View.Directory)
+	assert.Equal(t, ".gohtml", config.View.Extension)
+}
+
+func TestLoadConfigGeneratedMicroservice(t *testing.T) {
+	service := &MicroserviceConfig{Name: "orders", Description: "Orders", Port: 8081, WithDB: true, WithAuth: true, WithCache: true, WithQueue: true}
+	t.Setenv("JWT_SECRET", "jwt-secret")
+	path := writeTestConfig(t, t.TempDir(), "forge.yaml", generateMicroserviceConfigFile(service))
+
+	config, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, 8081, config.Server.Port)
+	assert.Equal(t, 5*time.Minute, config.Database.ConnMaxLife)
+	assert.Equal(t, "jwt-secret", config.Auth.SecretKey)
+	assert.Equal(t, 24*time.Hour, config.Auth.TokenDuration, "expiration: 86400s")
+
+	t.Setenv("FORGE_AUTH_JWT_EXPIRATION", "3600")
+	config, err = LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, config.Auth.TokenDuration)
+
+	t.Setenv("FORGE_AUTH_JWT_EXPIRATION", "12h")
+	config, err = LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, 12*time.Hour, config.Auth.TokenDuration, "durations work in the environment too")
+
+	t.Setenv("FORGE_AUTH_JWT_EXPIRATION", "soon")
+	_, err = LoadConfig(path)
+	assert.ErrorContains(t, err, `"soon" is neither a number of seconds nor a duration`)
+}
+
+func TestLoadConfigEnvOverrides(t *testing.T) {
+	path := writeTestConfig(t, t.TempDir(), "forge.yaml", testConfigYAML)
+
+	t.Setenv("FORGE_SERVER_PORT", "8080")
+	t.Setenv("FORGE_SERVER_HOST", "0.0.0.0")
+	t.Setenv("FORGE_DATABASE_DEFAULT_DRIVER", "postgres")
+	t.Setenv("FORGE_DATABASE_DEFAULT_CONN_MAX_LIFE", "5m")
+	t.Setenv("FORGE_DATABASE_DEFAULT_LOG_LEVEL", "silent")
+	t.Setenv("FORGE_AUTH_JWT_SECRET_KEY", "env-secret")
+	t.Setenv("FORGE_APP_DEBUG", "false")
+
+	config, err := LoadConfig(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, 8080, config.Server.Port)
+	assert.Equal(t, "0.0.0.0", config.Server.Host)
+	assert.Equal(t, "postgres", config.Database.Driver)
+	assert.Equal(t, 5*time.Minute, config.Database.ConnMaxLife)
+	assert.Equal(t, logger.Silent, config.Database.LogLevel)
+	assert.Equal(t, "env-secret", config.Auth.SecretKey)
+	assert.False(t, config.Debug)
+}
+
+func TestLoadConfigInvalidEnvValue(t *testing.T) {
+	path := writeTestConfig(t, t.TempDir(), "forge.yaml", testConfigYAML)
+	t.Setenv("FORGE_SERVER_PORT", "not-a-port")
+
+	_, err := LoadConfig(path)
+	assert.ErrorContains(t, err, "FORGE_SERVER_PORT")
+}
+
+func TestLoadConfigProfiles(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestConfig(t, dir, "forge.yaml", testConfigYAML)
+	writeTestConfig(t, dir, "forge.production.yaml", `
+app:
+  debug: false
+server:
+  port: 80
+`)
+	writeTestConfig(t, dir, "forge.local.yaml", `
+server:
+  host: "127.0.0.1"
+`)
+
+	config, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, EnvDevelopment, config.Environment)
+	assert.Equal(t, 3000, config.Server.Port)
+	assert.Equal(t, "127.0.0.1", config.Server.Host)
+
+	t.Setenv("FORGE_ENV", EnvProduction)
+	config, err = LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, EnvProduction, config.Environment)
+	assert.Equal(t, 80, config.Server.Port)
+	assert.Equal(t, "127.0.0.1", config.Server.Host)
+	assert.False(t, config.Debug)
+	assert.Equal(t, "shop", config.Name)
+
+	t.Setenv("FORGE_SERVER_PORT", "8443")
+	config, err = LoadConfig(path, WithEnvironment("staging"))
+	require.NoError(t, err)
+	assert.Equal(t, "staging", config.Environment)
+	assert.Equal(t, 8443, config.Server.Port)
+	assert.True(t, config.Debug)
+}
+
+func TestLoadConfigNamedDatabases(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestConfig(t, dir, "forge.yaml", strings.Replace(testConfigYAML, "\nauth:", `
+  analytics:
+    driver: "postgres"
+    host: "analytics-primary"
+    port: 5432
+    name: "events"
+    replicas:
+      - host: "analytics-replica-1"
+      - host: "analytics-replica-2"
+        port: 6432
+
+auth:`, 1))
+	writeTestConfig(t, dir, "forge.production.yaml", `
+database:
+  default:
+    name: "shop_prod.db"
+`)
+
+	t.Setenv("FORGE_ENV", EnvProduction)
+	t.Setenv("FORGE_DATABASE_ANALYTICS_PASSWORD", "s3cret")
+
+	config, err := LoadConfig(path)
+	require.NoError(t, err)
+
+	require.Len(t, config.Databases, 2)
+	assert.Equal(t, "sqlite", config.Databases["default"].Driver)
+	assert.Equal(t, "shop_prod.db", config.Databases["default"].Name)
+	assert.Equal(t, config.Databases["default"], config.Database)
+
+	analytics := config.Databases["analytics"]
+	assert.Equal(t, "analytics-primary", analytics.Host)
+	assert.Equal(t, "s3cret", analytics.Password)
+	require.Len(t, analytics.Replicas, 2)
+	assert.Equal(t, "analytics-replica-1", analytics.Replicas[0].Host)
+
+	replica := analytics.replica(analytics.Replicas[1])
+	assert.Equal(t, "analytics-replica-2", replica.Host)
+	assert.Equal(t, 6432, replica.Port)
+	assert.Equal(t, "events", replica.Name)
+	assert.Equal(t, "s3cret", replica.Password)
+}
+
+func TestLoadConfigResolvesSecrets(t *testing.T) {
+	dir := t.TempDir()
+	writeTestConfig(t, dir, "jwt_key", "jwt-from-file\n")
+	path := writeTestConfig(t, dir, "forge.yaml", `
+server:
+  port: ${SHOP_PORT}
+database:
+  default:
+    driver: "sqlite"
+    name: "${SHOP_DB:-shop.db}"
+    password: ${SHOP_DB_PASSWORD}
+auth:
+  jwt:
+    secret_key_file: "`+filepath.Join(dir, "jwt_key")+`"
+`)
+
+	provider := secrets.ProviderFunc(func(ctx context.Context, key string) (string, error) {
+		switch key {
+		case "SHOP_PORT":
+			return "8080", nil
+		case "SHOP_DB_PASSWORD":
+			return "123456", nil
+		}
+		return "", secrets.ErrNotFound
+	})
+
+	config, err := LoadConfig(path, WithSecretProvider(provider))
+	require.NoError(t, err)
+	assert.Equal(t, 8080, config.Server.Port)
+	assert.Equal(t, "shop.db", config.Database.Name)
+	assert.Equal(t, "123456", config.Database.Password)
+	assert.Equal(t, "jwt-from-file", config.Auth.SecretKey)
+
+	_, err = LoadConfig(path, WithSecretProvider(secrets.Chain()))
+	assert.ErrorContains(t, err, "secret SHOP_PORT is not set")
+}
+
+func TestLoadConfigSecretFileFromEnv(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestConfig(t, dir, "forge.yaml", testConfigYAML)
+	t.Setenv("FORGE_DATABASE_DEFAULT_PASSWORD_FILE", writeTestConfig(t, dir, "db_password", "from-mount\n"))
+
+	config, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, "from-mount", config.Database.Password)
+}
+
+func TestConfigStringMasksSecrets(t *testing.T) {
+	config := Config{
+		Name:     "shop",
+		Database: DatabaseConfig{Driver: "postgres", Password: "db-pass", Replicas: []DatabaseConfig{{Password: "replica-pass"}}},
+		Auth:     auth.Config{SecretKey: "jwt-key"},
+		Mailer:   mailer.Config{Password: "smtp-pass"},
+	}
+
+	for _, out := range []string{config.String(), fmt.Sprintf("%v", &config), fmt.Sprintf("%#v", config), config.Database.String()} {
+		assert.Contains(t, out, secrets.Mask)
+		for _, secret := range []string{"db-pass", "replica-pass", "jwt-key", "smtp-pass"} {
+			assert.NotContains(t, out, secret)
+		}
+	}
+	assert.Equal(t, "db-pass", config.Database.Password, "the original config is left untouched")
+	assert.Equal(t, "postgres", config.Redacted().Database.Driver)
+}
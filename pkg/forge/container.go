@@ -0,0 +1,451 @@
+package forge
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Scope controls how long a provided instance lives.
+type Scope int
+
+const (
+	// ScopeSingleton builds the instance once and shares it.
+	ScopeSingleton Scope = iota
+	// ScopeRequest builds a fresh instance for every request that needs it.
+	ScopeRequest
+)
+
+func (s Scope) String() string {
+	if s == ScopeRequest {
+		return "request"
+	}
+	return "singleton"
+}
+
+// ProvideOption customises a provider registered with Provide.
+type ProvideOption func(*provider)
+
+// WithScope sets the provider scope. Providers are singletons by default.
+func WithScope(scope Scope) ProvideOption {
+	return func(p *provider) {
+		p.scope = scope
+	}
+}
+
+// Named registers the provider under name. Named providers are only injected
+// into fields tagged `inject:"name"` or resolved with ResolveNamed.
+func Named(name string) ProvideOption {
+	return func(p *provider) {
+		p.name = name
+	}
+}
+
+var (
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	contextType = reflect.TypeOf((*Context)(nil))
+	lockerType  = reflect.TypeOf((*sync.Locker)(nil)).Elem()
+
+	errNoProvider = errors.New("no provider")
+)
+
+type provider struct {
+	name   string
+	typ    reflect.Type
+	scope  Scope
+	fn     reflect.Value
+	params []reflect.Type
+
+	mu       sync.Mutex
+	value    reflect.Value
+	resolved bool
+}
+
+func (p *provider) String() string {
+	if p.name != "" {
+		return fmt.Sprintf("%s (%s)", p.typ, p.name)
+	}
+	return p.typ.String()
+}
+
+type dependency struct {
+	typ  reflect.Type
+	name string
+}
+
+func (d dependency) String() string {
+	if d.name != "" {
+		return fmt.Sprintf("%s named %q", d.typ, d.name)
+	}
+	return d.typ.String()
+}
+
+type container struct {
+	mu        sync.RWMutex
+	providers []*provider
+}
+
+// Provide registers a constructor with the dependency container. The
+// constructor is a function returning the provided value and optionally an
+// error; its parameters are resolved from the container, and request-scoped
+// constructors may also take the current *Context. Any other value is
+// registered as a ready-made singleton.
+//
+// Request-scoped values are injected into a per-request copy of the
+// controller, so handlers of a controller with request-scoped fields must
+// not change its other fields, and the controller must not hold a lock by
+// value. Use Context.Resolve for such controllers instead.
+//
+// Providing the same type and name again replaces the earlier provider.
+func (app *Application) Provide(constructor interface{}, opts ...ProvideOption) error {
+	if constructor == nil {
+		return errors.New("provide: constructor is nil")
+	}
+
+	p := &provider{}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	fn := reflect.ValueOf(constructor)
+	if fn.Kind() != reflect.Func {
+		if p.scope == ScopeRequest {
+			return fmt.Errorf("provide: %s is a value and cannot be request-scoped", fn.Type())
+		}
+		p.typ = fn.Type()
+		p.value = fn
+		p.resolved = true
+		app.container.add(p)
+		return nil
+	}
+
+	t := fn.Type()
+	switch {
+	case t.NumOut() == 1 && t.Out(0) != errorType:
+	case t.NumOut() == 2 && t.Out(0) != errorType && t.Out(1) == errorType:
+	default:
+		return fmt.Errorf("provide: constructor %s must return a value and optionally an error", t)
+	}
+
+	p.typ = t.Out(0)
+	p.fn = fn
+	for i := 0; i < t.NumIn(); i++ {
+		param := t.In(i)
+		if param == contextType && p.scope != ScopeRequest {
+			return fmt.Errorf("provide: %s depends on *forge.Context and must be request-scoped", p)
+		}
+		p.params = append(p.params, param)
+	}
+
+	app.container.add(p)
+	return nil
+}
+
+func (c *container) add(p *provider) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, existing := range c.providers {
+		if existing.typ == p.typ && existing.name == p.name {
+			c.providers[i] = p
+			return
+		}
+	}
+	c.providers = append(c.providers, p)
+}
+
+// lookup finds the provider for dep. Unnamed dependencies match the exact
+// type first and otherwise the single provider implementing an interface.
+func (c *container) lookup(dep dependency) (*provider, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if dep.name != "" {
+		var mismatch *provider
+		for _, p := range c.providers {
+			if p.name != dep.name {
+				continue
+			}
+			if p.typ.AssignableTo(dep.typ) {
+				return p, nil
+			}
+			mismatch = p
+		}
+		if mismatch != nil {
+			return nil, fmt.Errorf("provider %s is not assignable to %s", mismatch, dep.typ)
+		}
+		return nil, fmt.Errorf("%w for %s", errNoProvider, dep)
+	}
+
+	var candidates []*provider
+	for _, p := range c.providers {
+		if p.name != "" {
+			continue
+		}
+		if p.typ == dep.typ {
+			return p, nil
+		}
+		if dep.typ.Kind() == reflect.Interface && p.typ.Implements(dep.typ) {
+			candidates = append(candidates, p)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, fmt.Errorf("%w for %s", errNoProvider, dep)
+	case 1:
+		return candidates[0], nil
+	default:
+		names := make([]string, len(candidates))
+		for i, p := range candidates {
+			names[i] = p.String()
+		}
+		return nil, fmt.Errorf("ambiguous providers for %s: %s", dep, strings.Join(names, ", "))
+	}
+}
+
+// providesExactly reports whether an unnamed provider has exactly type t.
+func (c *container) providesExactly(t reflect.Type) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, p := range c.providers {
+		if p.name == "" && p.typ == t {
+			return true
+		}
+	}
+	return false
+}
+
+// check walks the dependency graph of dep without building anything and
+// reports the effective scope: request if anything on the path is
+// request-scoped. Cycles and missing providers are returned as errors.
+func (c *container) check(dep dependency, path []*provider) (Scope, error) {
+	p, err := c.lookup(dep)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, seen := range path {
+		if seen == p {
+			cycle := make([]string, 0, len(path)-i+1)
+			for _, q := range path[i:] {
+				cycle = append(cycle, q.String())
+			}
+			cycle = append(cycle, p.String())
+			return 0, fmt.Errorf("dependency cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	path = append(path, p)
+	for _, param := range p.params {
+		if param == contextType {
+			continue
+		}
+		scope, err := c.check(dependency{typ: param}, path)
+		if err != nil {
+			return 0, err
+		}
+		if scope == ScopeRequest && p.scope == ScopeSingleton {
+			return 0, fmt.Errorf("singleton %s depends on request-scoped %s", p, param)
+		}
+	}
+	return p.scope, nil
+}
+
+// resolve builds or returns the instance for dep. Request-scoped instances
+// need ctx and are cached for the lifetime of the request.
+func (c *container) resolve(dep dependency, ctx *Context) (reflect.Value, error) {
+	scope, err := c.check(dep, nil)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if scope == ScopeRequest && ctx == nil {
+		return reflect.Value{}, fmt.Errorf("%s is request-scoped and can only be resolved during a request", dep)
+	}
+
+	p, err := c.lookup(dep)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return c.build(p, ctx)
+}
+
+func (c *container) build(p *provider, ctx *Context) (reflect.Value, error) {
+	if p.scope == ScopeRequest {
+		instances := ctx.scopedInstances()
+		if v, ok := instances[p]; ok {
+			return v, nil
+		}
+		v, err := c.call(p, ctx)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		instances[p] = v
+		return v, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.resolved {
+		return p.value, nil
+	}
+	v, err := c.call(p, ctx)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	p.value = v
+	p.resolved = true
+	return v, nil
+}
+
+func (c *container) call(p *provider, ctx *Context) (reflect.Value, error) {
+	args := make([]reflect.Value, len(p.params))
+	for i, param := range p.params {
+		if param == contextType {
+			args[i] = reflect.ValueOf(ctx)
+			continue
+		}
+		dep, err := c.lookup(dependency{typ: param})
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if args[i], err = c.build(dep, ctx); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+
+	out := p.fn.Call(args)
+	if len(out) == 2 && !out[1].IsNil() {
+		return reflect.Value{}, fmt.Errorf("failed to construct %s: %w", p, out[1].Interface().(error))
+	}
+	return out[0], nil
+}
+
+// injectField is a controller field filled from the container.
+type injectField struct {
+	index int
+	dep   dependency
+}
+
+// inject fills the exported fields of a struct controller. Fields tagged
+// `inject:""` are matched by type and `inject:"name"` by provider name;
+// both fail when no provider exists. Untagged fields are only filled by a
+// provider of exactly their type; an interface implemented by a provider
+// needs the tag. Fields that are already set are left alone. Singletons are set right away and the request-scoped fields are
+// returned to be filled on every request, which copies the controller.
+func (app *Application) inject(controller interface{}) ([]injectField, error) {
+	v := reflect.ValueOf(controller)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil, nil
+	}
+	v = v.Elem()
+	t := v.Type()
+
+	var requestFields []injectField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		tag, tagged := field.Tag.Lookup("inject")
+		if tag == "-" || !v.Field(i).IsZero() {
+			continue
+		}
+		if !tagged && (field.Anonymous || !app.container.providesExactly(field.Type)) {
+			continue
+		}
+
+		dep := dependency{typ: field.Type, name: tag}
+		scope, err := app.container.check(dep, nil)
+		if err != nil {
+			return nil, fmt.Errorf("cannot inject %s.%s: %w", t.Name(), field.Name, err)
+		}
+
+		if scope == ScopeRequest {
+			requestFields = append(requestFields, injectField{index: i, dep: dep})
+			continue
+		}
+
+		value, err := app.container.resolve(dep, nil)
+		if err != nil {
+			return nil, fmt.Errorf("cannot inject %s.%s: %w", t.Name(), field.Name, err)
+		}
+		v.Field(i).Set(value)
+	}
+
+	if len(requestFields) > 0 && holdsLock(t) {
+		return nil, fmt.Errorf("cannot inject request-scoped fields into %s: it holds a lock and is copied for every request, use ctx.Resolve instead", t.Name())
+	}
+	return requestFields, nil
+}
+
+// holdsLock reports whether values of t contain a lock, such as a
+// sync.Mutex, that copying would break.
+func holdsLock(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Struct:
+		if reflect.PtrTo(t).Implements(lockerType) {
+			return true
+		}
+		for i := 0; i < t.NumField(); i++ {
+			if holdsLock(t.Field(i).Type) {
+				return true
+			}
+		}
+	case reflect.Array:
+		return holdsLock(t.Elem())
+	}
+	return false
+}
+
+// withRequestFields returns a shallow copy of the controller with its
+// request-scoped fields resolved for ctx, leaving the shared controller
+// untouched. Changes the handler makes to the copy are dropped after the
+// request.
+func (app *Application) withRequestFields(controllerValue reflect.Value, fields []injectField, ctx *Context) (reflect.Value, error) {
+	scoped := reflect.New(controllerValue.Elem().Type())
+	scoped.Elem().Set(controllerValue.Elem())
+
+	for _, field := range fields {
+		value, err := app.container.resolve(field.dep, ctx)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		scoped.Elem().Field(field.index).Set(value)
+	}
+	return scoped, nil
+}
+
+// provideBuiltins makes the framework services injectable.
+func (app *Application) provideBuiltins() {
+	app.Provide(app)
+	app.Provide(app.config)
+	app.Provide(app.logger)
+	app.Provide(app.plugins)
+
+	if app.database != nil {
+		app.Provide(app.database)
+		app.Provide(app.database.DB)
+	}
+	for name, db := range app.databases {
+		app.Provide(db, Named(name))
+		app.Provide(db.DB, Named(name))
+	}
+	if app.auth != nil {
+		app.Provide(app.auth.JWTManager)
+	}
+	if app.mailer != nil {
+		app.Provide(app.mailer)
+	}
+	if app.queue != nil {
+		app.Provide(app.queue)
+	}
+	app.Provide(app.flags)
+	app.Provide(app.hub)
+	app.Provide(app.events)
+}
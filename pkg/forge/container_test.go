@@ -0,0 +1,177 @@
+package forge
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type Greeter interface {
+	Greet(name string) string
+}
+
+type politeGreeter struct {
+	prefix string
+}
+
+func (g *politeGreeter) Greet(name string) string {
+	return g.prefix + " " + name
+}
+
+type RequestID struct {
+	Value string
+}
+
+type GreetingController struct {
+	Controller
+	Greeter Greeter
+	Formal  Greeter  `inject:"formal"`
+	DB      *gorm.DB `inject:""`
+	Request *RequestID
+	Ignored string
+}
+
+func (c *GreetingController) HandleGetHello(ctx *Context) error {
+	var again *RequestID
+	if err := ctx.Resolve(&again); err != nil {
+		return err
+	}
+	return ctx.SendString(fmt.Sprintf("%s|%s|%s|%t",
+		c.Greeter.Greet("ann"), c.Formal.Greet("ann"), c.Request.Value, again == c.Request))
+}
+
+func TestRegisterControllerInjectsDependencies(t *testing.T) {
+	app := newTestApp(t, &Config{Database: DatabaseConfig{Driver: "sqlite", Name: "di.db"}})
+
+	require.NoError(t, app.Provide(func() Greeter { return &politeGreeter{prefix: "hi"} }))
+	require.NoError(t, app.Provide(func() (Greeter, error) { return &politeGreeter{prefix: "good day"}, nil }, Named("formal")))
+
+	requests := 0
+	require.NoError(t, app.Provide(func(ctx *Context) *RequestID {
+		requests++
+		return &RequestID{Value: fmt.Sprintf("%s-%d", ctx.Query("tag"), requests)}
+	}, WithScope(ScopeRequest)))
+
+	controller := &GreetingController{}
+	require.NoError(t, app.RegisterController(controller))
+	assert.Same(t, app.DB(), controller.DB)
+	assert.Nil(t, controller.Request, "request-scoped fields are only set per request")
+
+	for i := 1; i <= 2; i++ {
+		status, body := getBody(t, app, "/greeting/hello?tag=req")
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, fmt.Sprintf("hi ann|good day ann|req-%d|true", i), body)
+	}
+}
+
+func TestRegisterControllerKeepsPresetFields(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	require.NoError(t, app.Provide(&politeGreeter{prefix: "hi"}))
+	require.NoError(t, app.Provide(&politeGreeter{prefix: "dear"}, Named("formal")))
+
+	stub := &politeGreeter{prefix: "stub"}
+	controller := &GreetingController{Greeter: stub, DB: &gorm.DB{}}
+	require.NoError(t, app.RegisterController(controller))
+	assert.Same(t, stub, controller.Greeter)
+	assert.Equal(t, "dear ann", controller.Formal.Greet("ann"))
+}
+
+func TestRegisterControllerReportsMissingDependency(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	require.NoError(t, app.Provide(&politeGreeter{}))
+
+	err := app.RegisterController(&GreetingController{})
+	assert.ErrorContains(t, err, "GreetingController.Formal")
+	assert.ErrorContains(t, err, `named "formal"`)
+}
+
+type serviceA struct{ b *serviceB }
+type serviceB struct{ a *serviceA }
+
+type CycleController struct {
+	Controller
+	A *serviceA `inject:""`
+}
+
+func TestRegisterControllerReportsCycles(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	require.NoError(t, app.Provide(func(b *serviceB) *serviceA { return &serviceA{b: b} }))
+	require.NoError(t, app.Provide(func(a *serviceA) *serviceB { return &serviceB{a: a} }))
+
+	err := app.RegisterController(&CycleController{})
+	assert.ErrorContains(t, err, "dependency cycle: *forge.serviceA -> *forge.serviceB -> *forge.serviceA")
+}
+
+type LooseController struct {
+	Controller
+	Any    interface{}
+	Out    io.Writer
+	Tagged Greeter `inject:""`
+}
+
+func (c *LooseController) HandleGetHello(ctx *Context) error { return nil }
+
+func TestUntaggedFieldsNeedExactType(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	require.NoError(t, app.Provide(&politeGreeter{prefix: "hi"}))
+	require.NoError(t, app.Provide(&bytes.Buffer{}))
+
+	controller := &LooseController{}
+	require.NoError(t, app.RegisterController(controller))
+	assert.Nil(t, controller.Any)
+	assert.Nil(t, controller.Out, "interfaces are only matched when tagged")
+	assert.Equal(t, "hi ann", controller.Tagged.Greet("ann"))
+}
+
+type LockedController struct {
+	Controller
+	mu      sync.Mutex
+	Request *RequestID
+}
+
+func (c *LockedController) HandleGetHello(ctx *Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return ctx.SendString(c.Request.Value)
+}
+
+func TestRequestScopeRejectsControllerWithLock(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	require.NoError(t, app.Provide(func(ctx *Context) *RequestID { return &RequestID{} }, WithScope(ScopeRequest)))
+
+	err := app.RegisterController(&LockedController{})
+	assert.ErrorContains(t, err, "cannot inject request-scoped fields into LockedController")
+	assert.True(t, holdsLock(reflect.TypeOf([2]sync.RWMutex{})))
+	assert.False(t, holdsLock(reflect.TypeOf(&sync.Mutex{})), "pointers are shared, not copied")
+}
+
+func TestSingletonCannotDependOnRequestScope(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	require.NoError(t, app.Provide(func(ctx *Context) *RequestID { return &RequestID{} }, WithScope(ScopeRequest)))
+	require.NoError(t, app.Provide(func(r *RequestID) *serviceA { return &serviceA{} }))
+
+	err := app.RegisterController(&CycleController{})
+	assert.ErrorContains(t, err, "singleton *forge.serviceA depends on request-scoped *forge.RequestID")
+}
+
+func TestProvideValidatesConstructors(t *testing.T) {
+	app := newTestApp(t, &Config{})
+
+	assert.Error(t, app.Provide(func() {}))
+	assert.Error(t, app.Provide(func() (int, string) { return 0, "" }))
+	assert.Error(t, app.Provide(func(ctx *Context) *RequestID { return nil }))
+	assert.Error(t, app.Provide(&RequestID{}, WithScope(ScopeRequest)))
+
+	require.NoError(t, app.Provide(func() (*serviceA, error) { return nil, errors.New("boom") }))
+	err := app.RegisterController(&CycleController{})
+	assert.ErrorContains(t, err, "boom")
+}
@@ -1,6 +1,11 @@
 package forge
 
 import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"reflect"
+
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -26,7 +31,12 @@ func (c *Context) JSON(data interface{}) error {
 	return c.Ctx.JSON(data)
 }
 
+// Bind decodes the request body into v with the renderer registered for
+// its content type, falling back to Fiber's body parser for forms.
 func (c *Context) Bind(v interface{}) error {
+	if decoder := c.requestDecoder(); decoder != nil {
+		return decoder.Decode(c.Body(), v)
+	}
 	return c.Ctx.BodyParser(v)
 }
 
@@ -53,4 +63,60 @@ func (c *Context) SetHeader(key, value string) {
 func (c *Context) Status(code int) *Context {
 	c.Ctx.Status(code)
 	return c
-}
\ No newline at end of file
+}
+
+// ClientCertificate returns the verified client certificate of a mutual TLS
+// connection, or nil when the client did not present one.
+func (c *Context) ClientCertificate() *x509.Certificate {
+	state := c.Ctx.Context().TLSConnectionState()
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return state.VerifiedChains[0][0]
+}
+
+// FeatureEnabled reports whether the named feature flag is on for the current
+// user, identified by the user_id local set by middleware.RequireAuth.
+func (c *Context) FeatureEnabled(name string) bool {
+	if c.app.flags == nil {
+		return false
+	}
+	userID := ""
+	if id := c.Locals("user_id"); id != nil {
+		userID = fmt.Sprint(id)
+	}
+	return c.app.flags.Enabled(name, userID)
+}
+
+// scopedInstancesKey stores the request-scoped dependencies in fiber Locals.
+const scopedInstancesKey = "forge.scoped"
+
+func (c *Context) scopedInstances() map[*provider]reflect.Value {
+	if instances, ok := c.Ctx.Locals(scopedInstancesKey).(map[*provider]reflect.Value); ok {
+		return instances
+	}
+	instances := make(map[*provider]reflect.Value)
+	c.Ctx.Locals(scopedInstancesKey, instances)
+	return instances
+}
+
+// Resolve fills target, a pointer, with the provided instance of its type.
+// Request-scoped instances are shared for the rest of the request.
+func (c *Context) Resolve(target interface{}) error {
+	return c.ResolveNamed("", target)
+}
+
+// ResolveNamed fills target with the instance provided under name.
+func (c *Context) ResolveNamed(name string, target interface{}) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return errors.New("resolve: target must be a non-nil pointer")
+	}
+
+	value, err := c.app.container.resolve(dependency{typ: v.Elem().Type(), name: name}, c)
+	if err != nil {
+		return err
+	}
+	v.Elem().Set(value)
+	return nil
+}
@@ -12,22 +12,52 @@ type Controller struct {
 	middleware []MiddlewareFunc
 }
 
+// RouteMetadata overrides the route of a handler when returned from an
+// optional Routes() []RouteMetadata method on a controller. Handler names
+// the Go method, e.g. "HandleGetPosts", or a resource action such as "Show"
+// for controllers mounted with Resource; an empty Method or Path keeps the
+// one derived from the name. Path is mounted under the controller's base
+// path and version, replacing the controller name segment, so
+// "/users/:userId/posts/:postId" serves /api/v1/users/:userId/posts/:postId.
+// Listing a handler more than once serves it on several routes.
+//
+// The remaining fields document the route in the OpenAPI document: Response
+// is the 200 response and Responses holds others by status code.
 type RouteMetadata struct {
+	Handler     string
 	Method      string
 	Path        string
 	Description string
+	Params      []RouteParam
 	RequestBody interface{}
 	Response    interface{}
+	Responses   map[int]interface{}
+}
+
+// RouteParam documents a path, query or header parameter. Type is an
+// OpenAPI type: string, integer, number or boolean.
+type RouteParam struct {
+	Name        string
+	In          string
+	Type        string
+	Required    bool
+	Description string
 }
 
 type HandlerFunc func(*Context) error
 
 type MiddlewareFunc func(HandlerFunc) HandlerFunc
 
+// Use adds middleware wrapping every handler of the controller. It must be
+// called before the controller is registered.
 func (c *Controller) Use(middleware ...MiddlewareFunc) {
 	c.middleware = append(c.middleware, middleware...)
 }
 
+func (c *Controller) controllerMiddleware() []MiddlewareFunc {
+	return c.middleware
+}
+
 func (c *Controller) RegisterRoutes(router fiber.Router) {
 	t := reflect.TypeOf(c)
 	for i := 0; i < t.NumMethod(); i++ {
@@ -121,11 +151,11 @@ func (c *Controller) App() *Application {
 	return c.app
 }
 
+// Group starts a group of controllers served under prefix. The group
+// doesn't inherit c's middleware: each controller runs its own when it is
+// registered.
 func (c *Controller) Group(prefix string) *ControllerGroup {
-	return &ControllerGroup{
-		prefix:     prefix,
-		middleware: c.middleware,
-	}
+	return &ControllerGroup{prefix: prefix}
 }
 
 type ControllerGroup struct {
@@ -141,16 +171,17 @@ func (g *ControllerGroup) Use(middleware ...MiddlewareFunc) *ControllerGroup {
 
 func (g *ControllerGroup) Add(controller interface{}) *ControllerGroup {
 	g.controllers = append(g.controllers, controller)
-
-	if c, ok := controller.(*Controller); ok {
-		c.middleware = append(c.middleware, g.middleware...)
-	}
-
 	return g
 }
 
-func (g *ControllerGroup) Register(app *Application) {
+// Register mounts the controllers of the group under its prefix, wrapping
+// their handlers in the group's middleware before their own.
+func (g *ControllerGroup) Register(app *Application) error {
 	for _, controller := range g.controllers {
-		app.RegisterController(controller)
+		entry := controllerEntry{prefix: g.prefix, version: controllerVersion(controller), middleware: g.middleware}
+		if err := app.registerController(controller, entry); err != nil {
+			return err
+		}
 	}
+	return nil
 }
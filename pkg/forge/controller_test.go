@@ -0,0 +1,247 @@
+package forge
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type PostController struct {
+	Controller
+}
+
+func (c *PostController) Routes() []RouteMetadata {
+	return []RouteMetadata{
+		{
+			Handler:     "HandleGetComments",
+			Path:        "/users/:userId/posts/:postId/comments",
+			Description: "Comments of a post",
+			Params: []RouteParam{
+				{Name: "userId", In: "path", Type: "integer", Description: "User ID"},
+				{Name: "limit", In: "query", Type: "integer"},
+			},
+			Responses: map[int]interface{}{http.StatusNotFound: nil},
+		},
+		{Handler: "HandlePublish", Method: "POST", Path: "/posts/:postId/publish"},
+		{Handler: "HandlePublish", Method: "PUT", Path: "/posts/:postId/publish"},
+	}
+}
+
+func (c *PostController) HandleGetComments(ctx *Context) error {
+	return ctx.SendString(ctx.Param("userId") + "/" + ctx.Param("postId"))
+}
+
+func (c *PostController) HandlePublish(ctx *Context) error {
+	return ctx.SendString("published " + ctx.Param("postId"))
+}
+
+func (c *PostController) HandleGetPostById(ctx *Context) error {
+	return ctx.SendString("post " + ctx.Param("id"))
+}
+
+type UnknownHandlerController struct {
+	Controller
+}
+
+func (c *UnknownHandlerController) Routes() []RouteMetadata {
+	return []RouteMetadata{{Handler: "HandleMissing", Path: "/missing"}}
+}
+
+type DuplicateRouteController struct {
+	Controller
+}
+
+func (c *DuplicateRouteController) HandleGetById(ctx *Context) error       { return nil }
+func (c *DuplicateRouteController) HandleGetBySlug(ctx *Context) error     { return nil }
+func (c *DuplicateRouteController) HandlePostDuplicate(ctx *Context) error { return nil }
+
+type ConflictingController struct {
+	Controller
+}
+
+func (c *ConflictingController) Routes() []RouteMetadata {
+	return []RouteMetadata{{Handler: "HandleGetPost", Path: "/post/:postId"}}
+}
+
+func (c *ConflictingController) HandleGetPost(ctx *Context) error { return nil }
+
+type AccountController struct {
+	Controller
+}
+
+func (c *AccountController) Middleware() map[string][]MiddlewareFunc {
+	return map[string][]MiddlewareFunc{
+		"HandleGetSettings": {traceMiddleware("method")},
+	}
+}
+
+func (c *AccountController) HandleGetProfile(ctx *Context) error {
+	return ctx.SendString(string(ctx.Response().Header.Peek("X-Trace")))
+}
+
+func (c *AccountController) HandleGetSettings(ctx *Context) error {
+	return ctx.SendString(string(ctx.Response().Header.Peek("X-Trace")))
+}
+
+type UnknownMiddlewareController struct {
+	Controller
+}
+
+func (c *UnknownMiddlewareController) Middleware() map[string][]MiddlewareFunc {
+	return map[string][]MiddlewareFunc{"HandleMissing": {traceMiddleware("missing")}}
+}
+
+func (c *UnknownMiddlewareController) HandleGetIndex(ctx *Context) error { return nil }
+
+// traceMiddleware appends name to the X-Trace response header, recording
+// the order middleware runs in.
+func traceMiddleware(name string) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx *Context) error {
+			trace := string(ctx.Response().Header.Peek("X-Trace"))
+			if trace != "" {
+				trace += ","
+			}
+			ctx.Set("X-Trace", trace+name)
+			return next(ctx)
+		}
+	}
+}
+
+func TestParseRouteFromMethodName(t *testing.T) {
+	tests := map[string]RouteInfo{
+		"HandleGetIndex":                  {"GET", "/user"},
+		"HandleGetUsers":                  {"GET", "/user/users"},
+		"HandlePostUser":                  {"POST", "/user/user"},
+		"HandleGetUserPosts":              {"GET", "/user/user-posts"},
+		"HandleGetById":                   {"GET", "/user/:id"},
+		"HandleGetUserById":               {"GET", "/user/:id"},
+		"HandleDeleteUsersById":           {"DELETE", "/user/:id"},
+		"HandleGetPostsByUserIdAndPostId": {"GET", "/user/:userId/posts/:postId"},
+		"HandleGetOrdersByUserID":         {"GET", "/user/:userID/orders"},
+		"HandleGetCommentsByPostId":       {"GET", "/user/comments/:postId"},
+		"HandleGetUserByUserId":           {"GET", "/user/:userId"},
+		"HandlePutAvatarBySlug":           {"PUT", "/user/avatar/:slug"},
+		"HandleGetBy":                     {"GET", "/user/by"},
+	}
+	for name, want := range tests {
+		assert.Equal(t, want, parseRouteFromMethodName(name, "/user", "user"), name)
+	}
+
+	// Under a resource path, as with app.Resource("users", ...).
+	assert.Equal(t, RouteInfo{"GET", "/users/:userId/posts/:postId"},
+		parseRouteFromMethodName("HandleGetPostsByUserIdAndPostId", "/users", "user"))
+}
+
+func TestControllerRouteOverrides(t *testing.T) {
+	app := newTestApp(t, &Config{Server: ServerConfig{BasePath: "/api"}})
+	require.NoError(t, app.Version("v1").RegisterController(&PostController{}))
+
+	status, body := getBody(t, app, "/api/v1/users/7/posts/42/comments")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "7/42", body)
+
+	status, body = getBody(t, app, "/api/v1/post/3")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "post 3", body)
+
+	for _, method := range []string{http.MethodPost, http.MethodPut} {
+		route := findRoute(app.Routes(), method, "/api/v1/posts/:postId/publish")
+		require.NotNil(t, route, method)
+		assert.Equal(t, "HandlePublish", route.Handler)
+	}
+	assert.Nil(t, findRoute(app.Routes(), http.MethodGet, "/api/v1/post/publish"), "overridden convention route is gone")
+
+	spec, err := app.Version("v1").GenerateOpenAPI()
+	require.NoError(t, err)
+	operation := spec.Paths["/api/v1/users/{userId}/posts/{postId}/comments"].Get
+	require.NotNil(t, operation)
+	assert.Equal(t, "Comments of a post", operation.Description)
+	require.Len(t, operation.Parameters, 3)
+	assert.Equal(t, Parameter{Name: "userId", In: "path", Description: "User ID", Required: true, Schema: &Schema{Type: "integer"}}, *operation.Parameters[0])
+	assert.Equal(t, "string", operation.Parameters[1].Schema.Type, "undocumented path parameters keep their default")
+	assert.Equal(t, Parameter{Name: "limit", In: "query", Schema: &Schema{Type: "integer"}}, *operation.Parameters[2])
+	assert.Equal(t, "Not Found", operation.Responses["404"].Description)
+}
+
+func TestControllerRouteOverrideUnknownHandler(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	err := app.RegisterController(&UnknownHandlerController{})
+	assert.ErrorContains(t, err, `unknown handler "HandleMissing"`)
+}
+
+func TestRegisterControllerRejectsDuplicateRoutes(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	err := app.RegisterController(&DuplicateRouteController{})
+	assert.ErrorContains(t, err, "conflicts with DuplicateRouteController.")
+	assert.Nil(t, findRoute(app.Routes(), http.MethodPost, "/duplicateroute/duplicate"), "nothing is registered on conflict")
+
+	require.NoError(t, app.RegisterController(&PostController{}))
+	err = app.RegisterController(&ConflictingController{})
+	assert.ErrorContains(t, err, "route GET /post/:postId of ConflictingController.HandleGetPost conflicts with PostController.HandleGetPostById")
+
+	err = app.RegisterController(&PostController{})
+	assert.ErrorContains(t, err, "conflicts with PostController.")
+}
+
+func TestRegisterControllerAppliesMiddleware(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	controller := &AccountController{}
+	controller.Use(traceMiddleware("controller"))
+	require.NoError(t, app.RegisterController(controller))
+
+	_, body := getBody(t, app, "/account/profile")
+	assert.Equal(t, "controller", body)
+
+	_, body = getBody(t, app, "/account/settings")
+	assert.Equal(t, "controller,method", body)
+}
+
+func TestControllerGroupAppliesMiddleware(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	group := (&Controller{}).Group("/admin")
+	group.Use(traceMiddleware("group"))
+
+	controller := &AccountController{}
+	controller.Use(traceMiddleware("controller"))
+	require.NoError(t, group.Add(controller).Register(app))
+
+	status, body := getBody(t, app, "/admin/account/settings")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "group,controller,method", body)
+}
+
+func TestControllerInOwnGroupRunsMiddlewareOnce(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	calls := 0
+	controller := &AccountController{}
+	controller.Use(func(next HandlerFunc) HandlerFunc {
+		return func(ctx *Context) error {
+			calls++
+			return next(ctx)
+		}
+	})
+	require.NoError(t, controller.Group("/admin").Add(controller).Register(app))
+
+	status, _ := getBody(t, app, "/admin/account/profile")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, 1, calls)
+}
+
+func TestModuleMiddlewareWrapsControllerMiddleware(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	controller := &AccountController{}
+	controller.Use(traceMiddleware("controller"))
+	require.NoError(t, app.registerController(controller, controllerEntry{middleware: []MiddlewareFunc{traceMiddleware("module")}}))
+
+	_, body := getBody(t, app, "/account/settings")
+	assert.Equal(t, "module,controller,method", body)
+}
+
+func TestControllerMiddlewareUnknownHandler(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	err := app.RegisterController(&UnknownMiddlewareController{})
+	assert.ErrorContains(t, err, `UnknownMiddlewareController.Middleware: unknown handler "HandleMissing"`)
+}
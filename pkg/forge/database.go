@@ -1,6 +1,9 @@
 package forge
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -8,34 +11,72 @@ import (
 	"time"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
+	"github.com/BisiOlaYemi/forge/pkg/forge/secrets"
 	"github.com/glebarez/sqlite" 
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlserver"
 )
 
+// DefaultDatabase is the name of the connection returned by Application.DB.
+const DefaultDatabase = "default"
+
+// Database wraps a connection. With replicas configured, queries on DB are
+// routed to a replica while writes and transactions use the primary.
 type Database struct {
-	DB *gorm.DB
+	DB       *gorm.DB
+	primary  *gorm.DB
+	replicas []*sql.DB
 }
 
 type DatabaseConfig struct {
-	Driver        string        
-	Name          string        
-	Host          string        
-	Port          int           
-	Username      string        
-	Password      string        
-	SSLMode       string        
-	Charset       string        
-	Timezone      string        
-	MaxOpenConns  int           
-	MaxIdleConns  int           
-	ConnMaxLife   time.Duration 
-	SlowThreshold time.Duration 
-	LogLevel      logger.LogLevel 
-	Debug         bool          
+	Driver        string          `yaml:"driver"`
+	Name          string          `yaml:"name"`
+	Host          string          `yaml:"host"`
+	Port          int             `yaml:"port"`
+	Username      string          `yaml:"username"`
+	Password      string          `yaml:"password" secret:"true"`
+	SSLMode       string          `yaml:"ssl_mode"`
+	Charset       string          `yaml:"charset"`
+	Timezone      string          `yaml:"timezone"`
+	MaxOpenConns  int             `yaml:"max_open_conns"`
+	MaxIdleConns  int             `yaml:"max_idle_conns"`
+	ConnMaxLife   time.Duration   `yaml:"conn_max_life"`
+	SlowThreshold time.Duration   `yaml:"slow_threshold"`
+	LogLevel      logger.LogLevel `yaml:"-"`
+	Debug         bool            `yaml:"debug"`
+
+	// Replicas receive read queries. Unset fields are inherited from the
+	// primary, so usually only the host differs.
+	Replicas []DatabaseConfig `yaml:"replicas"`
+}
+
+// String formats the connection settings with the password masked.
+func (c DatabaseConfig) String() string {
+	type plain DatabaseConfig
+	return fmt.Sprintf("%+v", plain(secrets.Redact(c).(DatabaseConfig)))
 }
 
+// GoString keeps %#v from printing the password.
+func (c DatabaseConfig) GoString() string {
+	type plain DatabaseConfig
+	return fmt.Sprintf("%#v", plain(secrets.Redact(c).(DatabaseConfig)))
+}
+
+
+// databaseConfigs merges Database and Databases into the set of
+// connections to open, keyed by name.
+func (c *Config) databaseConfigs() map[string]DatabaseConfig {
+	configs := make(map[string]DatabaseConfig, len(c.Databases)+1)
+	if c.Database.Driver != "" {
+		configs[DefaultDatabase] = c.Database
+	}
+	for name, config := range c.Databases {
+		configs[name] = config
+	}
+	return configs
+}
 
 func DefaultDatabaseConfig() *DatabaseConfig {
 	return &DatabaseConfig{
@@ -94,18 +135,64 @@ func NewDatabase(config *DatabaseConfig) (*Database, error) {
 		},
 	)
 
+	dialector, err := config.dialector()
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger: gormLogger,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
 	
-	var dialector gorm.Dialector
-	var err error
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database instance: %w", err)
+	}
 	
+	if config.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(config.MaxIdleConns)
+	}
+	
+	if config.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(config.MaxOpenConns)
+	}
+	
+	if config.ConnMaxLife > 0 {
+		sqlDB.SetConnMaxLifetime(config.ConnMaxLife)
+	}
+
+	database := &Database{DB: db}
+	if len(config.Replicas) > 0 {
+		if err := database.useReplicas(config); err != nil {
+			database.Close()
+			return nil, err
+		}
+	}
+
+	if config.Debug {
+		db = db.Debug()
+	}
+
+	database.DB = db
+	if len(config.Replicas) > 0 {
+		database.primary = db.Clauses(dbresolver.Write).Session(&gorm.Session{})
+	}
+	return database, nil
+}
+
+func (config *DatabaseConfig) dialector() (gorm.Dialector, error) {
 	switch config.Driver {
 	case "sqlite":
-		dialector = sqlite.Open(config.Name)
+		return sqlite.Open(config.Name), nil
 	case "mysql":
 		dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=%s&parseTime=True&loc=%s",
 			config.Username, config.Password, config.Host, config.Port, config.Name,
 			config.Charset, config.Timezone)
-		dialector = mysql.Open(dsn)
+		return mysql.Open(dsn), nil
 	case "postgres":
 		sslMode := config.SSLMode
 		if sslMode == "" {
@@ -114,52 +201,101 @@ func NewDatabase(config *DatabaseConfig) (*Database, error) {
 		dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s TimeZone=%s",
 			config.Host, config.Port, config.Username, config.Password, config.Name, 
 			sslMode, config.Timezone)
-		dialector = postgres.Open(dsn)
+		return postgres.Open(dsn), nil
 	case "sqlserver":
 		dsn := fmt.Sprintf("sqlserver://%s:%s@%s:%d?database=%s",
 			config.Username, config.Password, config.Host, config.Port, config.Name)
-		dialector = sqlserver.Open(dsn)
+		return sqlserver.Open(dsn), nil
 	default:
 		return nil, fmt.Errorf("unsupported database driver: %s", config.Driver)
 	}
+}
 
-	
-	db, err := gorm.Open(dialector, &gorm.Config{
-		Logger: gormLogger,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
+// replica returns the primary config overlaid with the fields set on r.
+func (config *DatabaseConfig) replica(r DatabaseConfig) DatabaseConfig {
+	replica := *config
+	replica.Replicas = nil
+	if r.Host != "" {
+		replica.Host = r.Host
+	}
+	if r.Port != 0 {
+		replica.Port = r.Port
 	}
+	if r.Name != "" {
+		replica.Name = r.Name
+	}
+	if r.Username != "" {
+		replica.Username = r.Username
+	}
+	if r.Password != "" {
+		replica.Password = r.Password
+	}
+	if r.SSLMode != "" {
+		replica.SSLMode = r.SSLMode
+	}
+	return replica
+}
 
-	
-	sqlDB, err := db.DB()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get database instance: %w", err)
+// useReplicas registers the dbresolver plugin so reads go to the replicas.
+func (d *Database) useReplicas(config *DatabaseConfig) error {
+	dialectors := make([]gorm.Dialector, 0, len(config.Replicas))
+	for _, r := range config.Replicas {
+		replicaConfig := config.replica(r)
+		dialector, err := replicaConfig.dialector()
+		if err != nil {
+			return fmt.Errorf("invalid replica: %w", err)
+		}
+		dialectors = append(dialectors, trackedDialector{Dialector: dialector, database: d})
 	}
-	
+
+	resolver := dbresolver.Register(dbresolver.Config{
+		Replicas: dialectors,
+		Policy:   dbresolver.RandomPolicy{},
+	})
 	if config.MaxIdleConns > 0 {
-		sqlDB.SetMaxIdleConns(config.MaxIdleConns)
+		resolver.SetMaxIdleConns(config.MaxIdleConns)
 	}
-	
 	if config.MaxOpenConns > 0 {
-		sqlDB.SetMaxOpenConns(config.MaxOpenConns)
+		resolver.SetMaxOpenConns(config.MaxOpenConns)
 	}
-	
 	if config.ConnMaxLife > 0 {
-		sqlDB.SetConnMaxLifetime(config.ConnMaxLife)
+		resolver.SetConnMaxLifetime(config.ConnMaxLife)
 	}
 
-	
-	if config.Debug {
-		db = db.Debug()
+	if err := d.DB.Use(resolver); err != nil {
+		return fmt.Errorf("failed to connect to replicas: %w", err)
 	}
+	return nil
+}
 
-	return &Database{DB: db}, nil
+// trackedDialector records the pool opened for a replica so Close can release it.
+type trackedDialector struct {
+	gorm.Dialector
+	database *Database
+}
+
+func (t trackedDialector) Initialize(db *gorm.DB) error {
+	if err := t.Dialector.Initialize(db); err != nil {
+		return err
+	}
+	if sqlDB, ok := db.ConnPool.(*sql.DB); ok {
+		t.database.replicas = append(t.database.replicas, sqlDB)
+	}
+	return nil
+}
+
+// Primary returns a handle that always uses the primary connection, for
+// reads that must see the latest writes.
+func (d *Database) Primary() *gorm.DB {
+	if d.primary == nil {
+		return d.DB
+	}
+	return d.primary
 }
 
 
 func (d *Database) AutoMigrate(models ...interface{}) error {
-	return d.DB.AutoMigrate(models...)
+	return d.Primary().AutoMigrate(models...)
 }
 
 
@@ -203,7 +339,12 @@ func (d *Database) Close() error {
 	if err != nil {
 		return err
 	}
-	return sqlDB.Close()
+
+	errs := []error{sqlDB.Close()}
+	for _, replica := range d.replicas {
+		errs = append(errs, replica.Close())
+	}
+	return errors.Join(errs...)
 }
 
 
@@ -224,6 +365,15 @@ func (d *Database) Ping() error {
 	return sqlDB.Ping()
 }
 
+// CheckHealth pings the database for the readiness endpoint.
+func (d *Database) CheckHealth(ctx context.Context) error {
+	sqlDB, err := d.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
 
 func (d *Database) GetDriverName() string {
 	sqlDB, err := d.DB.DB()
@@ -303,7 +453,7 @@ func (m *MigrationManager) AddMigration(name string, up, down func(*gorm.DB) err
 
 func (m *MigrationManager) Migrate() error {
 	
-	err := m.DB.DB.Exec(`CREATE TABLE IF NOT EXISTS migrations (
+	err := m.DB.Primary().Exec(`CREATE TABLE IF NOT EXISTS migrations (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		name TEXT NOT NULL,
 		applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
@@ -314,7 +464,7 @@ func (m *MigrationManager) Migrate() error {
 	}
 
 	var appliedMigrations []string
-	err = m.DB.DB.Raw("SELECT name FROM migrations").Scan(&appliedMigrations).Error
+	err = m.DB.Primary().Raw("SELECT name FROM migrations").Scan(&appliedMigrations).Error
 	if err != nil {
 		return fmt.Errorf("failed to get applied migrations: %w", err)
 	}
@@ -351,7 +501,7 @@ func (m *MigrationManager) Migrate() error {
 func (m *MigrationManager) Rollback(steps int) error {
 	
 	var appliedMigrations []string
-	err := m.DB.DB.Raw("SELECT name FROM migrations ORDER BY id DESC LIMIT ?", steps).Scan(&appliedMigrations).Error
+	err := m.DB.Primary().Raw("SELECT name FROM migrations ORDER BY id DESC LIMIT ?", steps).Scan(&appliedMigrations).Error
 	if err != nil {
 		return fmt.Errorf("failed to get applied migrations: %w", err)
 	}
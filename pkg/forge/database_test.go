@@ -0,0 +1,60 @@
+package forge
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type replicatedNote struct {
+	ID   uint
+	Text string
+}
+
+func TestReplicasServeReadsAndPrimaryServesWrites(t *testing.T) {
+	dir := t.TempDir()
+	app := newTestApp(t, &Config{
+		Databases: map[string]DatabaseConfig{
+			"analytics": {
+				Driver:   "sqlite",
+				Name:     filepath.Join(dir, "primary.db"),
+				Replicas: []DatabaseConfig{{Name: filepath.Join(dir, "replica.db")}},
+			},
+		},
+	})
+
+	db := app.DatabaseNamed("analytics")
+	require.NotNil(t, db)
+	assert.Same(t, db.DB, app.DBNamed("analytics"))
+	assert.Nil(t, app.DBNamed("missing"))
+	assert.Nil(t, app.database, "no default connection was configured")
+
+	migrator, err := app.Migrator("analytics")
+	require.NoError(t, err)
+	migrator.AddMigration("create_notes", func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&replicatedNote{})
+	}, nil)
+	require.NoError(t, migrator.Migrate())
+	require.NoError(t, migrator.Migrate(), "applied migrations are read from the primary")
+
+	// The replica is a separate, empty file here, so reads that reach the
+	// primary are easy to tell apart.
+	require.NoError(t, db.DB.Create(&replicatedNote{Text: "hello"}).Error)
+
+	var count int64
+	assert.Error(t, db.DB.Model(&replicatedNote{}).Count(&count).Error, "reads go to the replica")
+
+	require.NoError(t, db.Primary().Model(&replicatedNote{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	require.NoError(t, db.Transaction(func(tx *gorm.DB) error {
+		return tx.Model(&replicatedNote{}).Count(&count).Error
+	}))
+	assert.Equal(t, int64(1), count)
+
+	_, err = app.Migrator("missing")
+	assert.ErrorContains(t, err, `"missing" is not configured`)
+}
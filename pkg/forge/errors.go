@@ -13,6 +13,7 @@ var (
 	ErrBadRequest    = NewAppError("bad request", http.StatusBadRequest)
 	ErrInternalError = NewAppError("internal server error", http.StatusInternalServerError)
 	ErrValidation    = NewAppError("validation error", http.StatusBadRequest)
+	ErrNotAcceptable = NewAppError("not acceptable", http.StatusNotAcceptable)
 )
 
 type AppError struct {
@@ -52,8 +53,9 @@ func (e *AppError) WithDetails(details map[string]interface{}) *AppError {
 
 func (e *AppError) WithDetail(key string, value interface{}) *AppError {
 	clone := *e
-	if clone.Details == nil {
-		clone.Details = make(map[string]interface{})
+	clone.Details = make(map[string]interface{}, len(e.Details)+1)
+	for k, v := range e.Details {
+		clone.Details[k] = v
 	}
 	clone.Details[key] = value
 	return &clone
@@ -0,0 +1,77 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/BisiOlaYemi/forge/pkg/forge/flags"
+)
+
+// FlagsConfig selects where feature flags are loaded from.
+type FlagsConfig struct {
+	Provider        string                `yaml:"provider"`         // memory (default), file or database
+	File            string                `yaml:"file"`             // defaults to config/flags.yaml
+	Database        string                `yaml:"database"`         // connection name, defaults to default
+	Table           string                `yaml:"table"`            // defaults to feature_flags
+	RefreshInterval time.Duration         `yaml:"refresh_interval"` // defaults to 30s
+	Flags           map[string]flags.Flag `yaml:"flags"`            // definitions for the memory provider
+}
+
+// setupFlags loads the feature flags; watchFlags keeps them fresh.
+func (app *Application) setupFlags() error {
+	config := app.config.Flags
+
+	var provider flags.Provider
+	switch config.Provider {
+	case "", "memory":
+		memory := flags.NewMemory()
+		for name, flag := range config.Flags {
+			flag.Name = name
+			memory.Set(flag)
+		}
+		provider = memory
+	case "file":
+		path := config.File
+		if path == "" {
+			path = "config/flags.yaml"
+		}
+		provider = flags.NewFile(path)
+	case "database":
+		name := config.Database
+		if name == "" {
+			name = DefaultDatabase
+		}
+		db := app.databases[name]
+		if db == nil {
+			return fmt.Errorf("database connection %q is not configured", name)
+		}
+		store := flags.NewDatabase(db.Primary(), config.Table)
+		if err := store.Migrate(); err != nil {
+			return err
+		}
+		provider = store
+	default:
+		return fmt.Errorf("unsupported flags provider: %s", config.Provider)
+	}
+
+	app.flags = flags.NewManager(provider)
+	if err := app.flags.Refresh(context.Background()); err != nil {
+		return err
+	}
+	return nil
+}
+
+// watchFlags refreshes the feature flags in the background until the
+// application shuts down. New starts it once nothing else can fail, so a
+// failed New leaves no goroutine behind.
+func (app *Application) watchFlags() {
+	go app.flags.Watch(app.config.Flags.RefreshInterval, app.closing, func(err error) {
+		app.logger.Error("Failed to refresh feature flags: %v", err)
+	})
+}
+
+// Flags returns the feature flag manager.
+func (app *Application) Flags() *flags.Manager {
+	return app.flags
+}
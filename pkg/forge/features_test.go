@@ -0,0 +1,83 @@
+package forge
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BisiOlaYemi/forge/pkg/forge/flags"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type CheckoutController struct {
+	Controller
+}
+
+func (c *CheckoutController) HandleGetCheckout(ctx *Context) error {
+	ctx.Locals("user_id", ctx.Query("user"))
+	if ctx.FeatureEnabled("new-checkout") {
+		return ctx.SendString("new")
+	}
+	return ctx.SendString("old")
+}
+
+func TestFeatureEnabled(t *testing.T) {
+	app := newTestApp(t, &Config{Flags: FlagsConfig{
+		Flags: map[string]flags.Flag{"new-checkout": {Enabled: true, Users: []string{"42"}}},
+	}})
+	require.NoError(t, app.RegisterController(&CheckoutController{}))
+
+	_, body := getBody(t, app, "/checkout/checkout?user=42")
+	assert.Equal(t, "new", body)
+	_, body = getBody(t, app, "/checkout/checkout?user=7")
+	assert.Equal(t, "old", body)
+
+	app.Flags().Provider().(*flags.Memory).Set(flags.Flag{Name: "new-checkout", Enabled: true})
+	require.NoError(t, app.Flags().Refresh(context.Background()))
+	_, body = getBody(t, app, "/checkout/checkout?user=7")
+	assert.Equal(t, "new", body)
+}
+
+func TestFlagsProviders(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("search:\n  enabled: true\n"), 0644))
+	app := newTestApp(t, &Config{Flags: FlagsConfig{Provider: "file", File: path}})
+	assert.True(t, app.Flags().Enabled("search", ""))
+
+	app = newTestApp(t, &Config{
+		Database: DatabaseConfig{Driver: "sqlite", Name: "flags.db"},
+		Flags:    FlagsConfig{Provider: "database"},
+	})
+	store := app.Flags().Provider().(*flags.Database)
+	require.NoError(t, store.Save(context.Background(), flags.Flag{Name: "search", Enabled: true}))
+	require.NoError(t, app.Flags().Refresh(context.Background()))
+	assert.True(t, app.Flags().Enabled("search", ""))
+
+	_, err := New(&Config{CORS: CORSConfig{AllowOrigins: "http://localhost"}, Flags: FlagsConfig{Provider: "consul"}})
+	assert.ErrorContains(t, err, "unsupported flags provider: consul")
+}
+
+func TestFailedNewDoesNotWatchFlags(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() { os.Chdir(wd) })
+	require.NoError(t, os.WriteFile("plugins", nil, 0644))
+
+	// Other tests' applications may still be watching their flags.
+	watchers := func() int {
+		var stacks strings.Builder
+		pprof.Lookup("goroutine").WriteTo(&stacks, 2)
+		return strings.Count(stacks.String(), "flags.(*Manager).Watch(")
+	}
+	before := watchers()
+
+	_, err = New(&Config{CORS: CORSConfig{AllowOrigins: "http://localhost"}})
+	require.ErrorContains(t, err, "failed to create plugin directory")
+	assert.Never(t, func() bool { return watchers() > before }, 200*time.Millisecond, 10*time.Millisecond, "the flag watcher is not left running")
+}
@@ -0,0 +1,74 @@
+package flags
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestFlagEnabledFor(t *testing.T) {
+	assert.False(t, Flag{Name: "off"}.EnabledFor("1"))
+	assert.True(t, Flag{Name: "on", Enabled: true}.EnabledFor(""))
+
+	beta := Flag{Name: "beta", Enabled: true, Users: []string{"7"}}
+	assert.True(t, beta.EnabledFor("7"))
+	assert.False(t, beta.EnabledFor("8"))
+	assert.False(t, beta.EnabledFor(""))
+
+	rollout := Flag{Name: "checkout", Enabled: true, Percentage: 30}
+	enabled := 0
+	for i := 0; i < 1000; i++ {
+		user := fmt.Sprint(i)
+		if rollout.EnabledFor(user) {
+			enabled++
+			assert.True(t, Flag{Name: "checkout", Enabled: true, Percentage: 60}.EnabledFor(user),
+				"raising the percentage keeps users that were already in")
+		}
+	}
+	assert.InDelta(t, 300, enabled, 60)
+}
+
+func TestManagerPicksUpChanges(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "flags.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("new-checkout:\n  enabled: false\n"), 0644))
+
+	manager := NewManager(NewFile(path))
+	require.NoError(t, manager.Refresh(ctx))
+	assert.False(t, manager.Enabled("new-checkout", "1"))
+
+	require.NoError(t, os.WriteFile(path, []byte("new-checkout:\n  enabled: true\n  users: [\"1\"]\n"), 0644))
+	require.NoError(t, manager.Refresh(ctx))
+	assert.True(t, manager.Enabled("new-checkout", "1"))
+	assert.False(t, manager.Enabled("new-checkout", "2"))
+
+	flag, ok := manager.Get("new-checkout")
+	require.True(t, ok)
+	assert.Equal(t, "new-checkout", flag.Name)
+
+	require.NoError(t, os.WriteFile(path, []byte("{not yaml"), 0644))
+	assert.Error(t, manager.Refresh(ctx))
+	assert.True(t, manager.Enabled("new-checkout", "1"), "a failed refresh keeps the last flags")
+}
+
+func TestDatabaseProvider(t *testing.T) {
+	ctx := context.Background()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "flags.db")), &gorm.Config{})
+	require.NoError(t, err)
+
+	store := NewDatabase(db, "")
+	require.NoError(t, store.Migrate())
+	require.NoError(t, store.Save(ctx, Flag{Name: "search", Enabled: true, Users: []string{"1", "2"}}))
+	require.NoError(t, store.Save(ctx, Flag{Name: "search", Enabled: true, Users: []string{"3"}}))
+
+	flags, err := store.Load(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Flag{"search": {Name: "search", Enabled: true, Users: []string{"3"}}}, flags)
+}
@@ -0,0 +1,139 @@
+package flags
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Memory holds flags in memory. Changes are picked up by a Manager on its
+// next refresh.
+type Memory struct {
+	mu    sync.RWMutex
+	flags map[string]Flag
+}
+
+func NewMemory(flags ...Flag) *Memory {
+	m := &Memory{flags: make(map[string]Flag)}
+	for _, flag := range flags {
+		m.flags[flag.Name] = flag
+	}
+	return m
+}
+
+func (m *Memory) Set(flag Flag) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.flags[flag.Name] = flag
+}
+
+func (m *Memory) Delete(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.flags, name)
+}
+
+func (m *Memory) Load(ctx context.Context) (map[string]Flag, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	flags := make(map[string]Flag, len(m.flags))
+	for name, flag := range m.flags {
+		flags[name] = flag
+	}
+	return flags, nil
+}
+
+// File loads flags from a YAML file mapping flag names to definitions:
+//
+//	new-checkout:
+//	  enabled: true
+//	  percentage: 25
+//	  users: ["42"]
+type File struct {
+	path string
+}
+
+func NewFile(path string) *File {
+	return &File{path: path}
+}
+
+func (f *File) Load(ctx context.Context) (map[string]Flag, error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read flags file: %w", err)
+	}
+
+	flags := make(map[string]Flag)
+	if err := yaml.Unmarshal(data, &flags); err != nil {
+		return nil, fmt.Errorf("failed to parse flags file %s: %w", f.path, err)
+	}
+	return flags, nil
+}
+
+// DefaultTable is the table used by the database provider.
+const DefaultTable = "feature_flags"
+
+// Record is a row of the feature flag table. Users holds comma-separated IDs.
+type Record struct {
+	Name       string `gorm:"primaryKey;size:191"`
+	Enabled    bool
+	Percentage int
+	Users      string
+	UpdatedAt  time.Time
+}
+
+// Database loads flags from a table, so they can be toggled without a deploy.
+type Database struct {
+	db    *gorm.DB
+	table string
+}
+
+func NewDatabase(db *gorm.DB, table string) *Database {
+	if table == "" {
+		table = DefaultTable
+	}
+	return &Database{db: db, table: table}
+}
+
+// Migrate creates or updates the flag table.
+func (d *Database) Migrate() error {
+	return d.db.Table(d.table).AutoMigrate(&Record{})
+}
+
+func (d *Database) Load(ctx context.Context) (map[string]Flag, error) {
+	var records []Record
+	if err := d.db.WithContext(ctx).Table(d.table).Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to load flags: %w", err)
+	}
+
+	flags := make(map[string]Flag, len(records))
+	for _, r := range records {
+		flag := Flag{Name: r.Name, Enabled: r.Enabled, Percentage: r.Percentage}
+		for _, user := range strings.Split(r.Users, ",") {
+			if user = strings.TrimSpace(user); user != "" {
+				flag.Users = append(flag.Users, user)
+			}
+		}
+		flags[r.Name] = flag
+	}
+	return flags, nil
+}
+
+// Save creates or updates a flag.
+func (d *Database) Save(ctx context.Context, flag Flag) error {
+	record := Record{
+		Name:       flag.Name,
+		Enabled:    flag.Enabled,
+		Percentage: flag.Percentage,
+		Users:      strings.Join(flag.Users, ","),
+	}
+	return d.db.WithContext(ctx).Table(d.table).Clauses(clause.OnConflict{UpdateAll: true}).Create(&record).Error
+}
@@ -0,0 +1,197 @@
+package forge
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	DefaultHealthTimeout  = 2 * time.Second
+	DefaultHealthCacheTTL = 5 * time.Second
+)
+
+// HealthChecker is implemented by components that can report whether they
+// are able to serve traffic. Plugins opt into readiness checks by
+// implementing it.
+type HealthChecker interface {
+	CheckHealth(ctx context.Context) error
+}
+
+// HealthCheckFunc adapts a function to the HealthChecker interface.
+type HealthCheckFunc func(ctx context.Context) error
+
+func (f HealthCheckFunc) CheckHealth(ctx context.Context) error {
+	return f(ctx)
+}
+
+// HealthConfig controls the built-in /healthz and /readyz endpoints.
+type HealthConfig struct {
+	Disabled      bool          `yaml:"disabled"`
+	LivenessPath  string        `yaml:"liveness_path"`  // defaults to /healthz
+	ReadinessPath string        `yaml:"readiness_path"` // defaults to /readyz
+	Timeout       time.Duration `yaml:"timeout"`        // per check, defaults to 2s
+	CacheTTL      time.Duration `yaml:"cache_ttl"`      // defaults to 5s, negative disables caching
+}
+
+const (
+	HealthStatusOK    = "ok"
+	HealthStatusError = "error"
+)
+
+// HealthReport is the readiness result served on /readyz.
+type HealthReport struct {
+	Status    string                     `json:"status"`
+	Checks    map[string]ComponentHealth `json:"checks"`
+	CheckedAt time.Time                  `json:"checked_at"`
+}
+
+type ComponentHealth struct {
+	Status  string `json:"status"`
+	Latency string `json:"latency"`
+	Error   string `json:"error,omitempty"`
+}
+
+type healthCheck struct {
+	name    string
+	checker HealthChecker
+}
+
+type healthCache struct {
+	mu      sync.Mutex
+	report  *HealthReport
+	expires time.Time
+}
+
+// AddHealthCheck registers a readiness check under name. Checks registered
+// with the same name replace each other.
+func (app *Application) AddHealthCheck(name string, checker HealthChecker) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	for i, check := range app.healthChecks {
+		if check.name == name {
+			app.healthChecks[i].checker = checker
+			return
+		}
+	}
+	app.healthChecks = append(app.healthChecks, healthCheck{name: name, checker: checker})
+}
+
+// Health runs every readiness check concurrently, each bounded by the
+// configured timeout, and reports the aggregated result.
+func (app *Application) Health(ctx context.Context) *HealthReport {
+	app.mu.RLock()
+	checks := append([]healthCheck(nil), app.healthChecks...)
+	app.mu.RUnlock()
+
+	if app.plugins != nil {
+		for _, p := range app.plugins.List() {
+			if checker, ok := p.(HealthChecker); ok {
+				checks = append(checks, healthCheck{name: "plugin:" + p.Name(), checker: checker})
+			}
+		}
+	}
+
+	timeout := app.config.Health.Timeout
+	if timeout <= 0 {
+		timeout = DefaultHealthTimeout
+	}
+
+	results := make([]ComponentHealth, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check healthCheck) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := withContext(checkCtx, func() error { return check.checker.CheckHealth(checkCtx) })
+			results[i] = ComponentHealth{Status: HealthStatusOK, Latency: time.Since(start).String()}
+			if err != nil {
+				results[i].Status = HealthStatusError
+				results[i].Error = err.Error()
+			}
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := &HealthReport{
+		Status:    HealthStatusOK,
+		Checks:    make(map[string]ComponentHealth, len(checks)),
+		CheckedAt: time.Now(),
+	}
+	for i, check := range checks {
+		report.Checks[check.name] = results[i]
+		if results[i].Status != HealthStatusOK {
+			report.Status = HealthStatusError
+		}
+	}
+	return report
+}
+
+// cachedHealth returns the last report while it is fresh so frequent probes
+// do not hit every dependency. Concurrent callers share a single run.
+func (app *Application) cachedHealth(ctx context.Context) *HealthReport {
+	ttl := app.config.Health.CacheTTL
+	if ttl == 0 {
+		ttl = DefaultHealthCacheTTL
+	}
+
+	app.health.mu.Lock()
+	defer app.health.mu.Unlock()
+
+	if app.health.report != nil && time.Now().Before(app.health.expires) {
+		return app.health.report
+	}
+
+	report := app.Health(ctx)
+	app.health.report = report
+	app.health.expires = report.CheckedAt.Add(ttl)
+	return report
+}
+
+func (app *Application) registerHealthRoutes() {
+	config := app.config.Health
+	if config.Disabled {
+		return
+	}
+
+	liveness := config.LivenessPath
+	if liveness == "" {
+		liveness = "/healthz"
+	}
+	readiness := config.ReadinessPath
+	if readiness == "" {
+		readiness = "/readyz"
+	}
+
+	app.server.Get(liveness, app.handleLiveness)
+	app.server.Get(readiness, app.handleReadiness)
+}
+
+func (app *Application) handleLiveness(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"status": HealthStatusOK})
+}
+
+func (app *Application) handleReadiness(c *fiber.Ctx) error {
+	select {
+	case <-app.closing:
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"status": HealthStatusError,
+			"error":  "shutting down",
+		})
+	default:
+	}
+
+	report := app.cachedHealth(c.UserContext())
+	if report.Status != HealthStatusOK {
+		c.Status(fiber.StatusServiceUnavailable)
+	}
+	return c.JSON(report)
+}
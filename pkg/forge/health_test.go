@@ -0,0 +1,101 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getReadiness(t *testing.T, app *Application) (int, HealthReport) {
+	t.Helper()
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var report HealthReport
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+	return resp.StatusCode, report
+}
+
+func TestLivenessEndpoint(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	app.AddHealthCheck("broken", HealthCheckFunc(func(ctx context.Context) error {
+		return errors.New("down")
+	}))
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestReadinessAggregatesChecks(t *testing.T) {
+	app := newTestApp(t, &Config{
+		Database: DatabaseConfig{Driver: "sqlite", Name: "health.db"},
+		Health:   HealthConfig{CacheTTL: -1},
+	})
+
+	status, report := getReadiness(t, app)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, HealthStatusOK, report.Status)
+	assert.Equal(t, HealthStatusOK, report.Checks["database"].Status)
+	assert.NotEmpty(t, report.Checks["database"].Latency)
+
+	app.AddHealthCheck("search", HealthCheckFunc(func(ctx context.Context) error {
+		return errors.New("connection refused")
+	}))
+
+	status, report = getReadiness(t, app)
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, HealthStatusError, report.Status)
+	assert.Equal(t, HealthStatusOK, report.Checks["database"].Status)
+	assert.Equal(t, HealthStatusError, report.Checks["search"].Status)
+	assert.Equal(t, "connection refused", report.Checks["search"].Error)
+}
+
+func TestReadinessTimesOutSlowChecks(t *testing.T) {
+	app := newTestApp(t, &Config{Health: HealthConfig{Timeout: 20 * time.Millisecond}})
+	app.AddHealthCheck("slow", HealthCheckFunc(func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}))
+
+	start := time.Now()
+	report := app.Health(context.Background())
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, HealthStatusError, report.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["slow"].Error)
+}
+
+func TestReadinessIsCached(t *testing.T) {
+	app := newTestApp(t, &Config{Health: HealthConfig{CacheTTL: time.Minute}})
+
+	var calls int32
+	app.AddHealthCheck("counter", HealthCheckFunc(func(ctx context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	}))
+
+	for i := 0; i < 3; i++ {
+		status, _ := getReadiness(t, app)
+		assert.Equal(t, http.StatusOK, status)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestReadinessFailsWhileShuttingDown(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	require.NoError(t, app.ShutdownWithContext(context.Background()))
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
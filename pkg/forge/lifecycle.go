@@ -0,0 +1,181 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// DefaultShutdownTimeout bounds Shutdown when Server.ShutdownTimeout is not set.
+const DefaultShutdownTimeout = 30 * time.Second
+
+// Hook is a lifecycle callback registered with OnStart or OnShutdown.
+type Hook func(ctx context.Context) error
+
+// OnStart registers hooks that run, in order, before the server starts
+// listening. The first failing hook aborts the start.
+func (app *Application) OnStart(hooks ...Hook) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+	app.startHooks = append(app.startHooks, hooks...)
+}
+
+// OnShutdown registers hooks that run during shutdown once requests and queue
+// jobs have drained. Hooks run in reverse registration order.
+func (app *Application) OnShutdown(hooks ...Hook) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+	app.shutdownHooks = append(app.shutdownHooks, hooks...)
+}
+
+// run starts background services, runs the start hooks and then blocks in
+// listen. With Server.HandleSignals set, SIGINT and SIGTERM trigger a
+// graceful shutdown and run returns once it has completed.
+func (app *Application) run(listen func() error) error {
+	if path := app.routesFile(); path != "" {
+		app.logger.Info("Writing the route table to %s instead of starting the server (%s is set)", path, RoutesFileEnv)
+		return app.writeRoutes(path)
+	}
+
+	app.mu.RLock()
+	hooks := append([]Hook(nil), app.startHooks...)
+	app.mu.RUnlock()
+
+	ctx := context.Background()
+	for _, hook := range hooks {
+		if err := hook(ctx); err != nil {
+			return fmt.Errorf("start hook failed: %w", err)
+		}
+	}
+
+	if err := app.startAdmin(); err != nil {
+		return err
+	}
+
+	if app.queue != nil {
+		app.queue.Start()
+	}
+
+	if !app.config.Server.HandleSignals {
+		return listen()
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- listen()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case sig := <-signals:
+		app.logger.Info("Received %s, shutting down", sig)
+		if err := app.Shutdown(); err != nil {
+			return err
+		}
+		return <-serveErr
+	}
+}
+
+// ShutdownWithContext gracefully stops the application. It closes WebSocket
+// connections and event streams, stops accepting connections, drains
+// in-flight requests, waits for running queue jobs, runs the shutdown hooks,
+// unloads plugins in reverse order and finally closes the databases. Every
+// step is bounded by ctx and all failures are returned joined.
+func (app *Application) ShutdownWithContext(ctx context.Context) error {
+	var errs []error
+
+	app.closeOnce.Do(func() { close(app.closing) })
+
+	if count := app.hub.Count(); count > 0 {
+		app.logger.Info("Closing %d WebSocket connections", count)
+	}
+	if err := app.hub.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close WebSocket connections: %w", err))
+	}
+	if err := app.events.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close event streams: %w", err))
+	}
+
+	app.logger.Info("Stopping HTTP server")
+	if err := app.server.ShutdownWithContext(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to shut down server: %w", err))
+	}
+	if err := app.shutdownRedirect(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to shut down HTTPS redirect: %w", err))
+	}
+	if err := app.shutdownAdmin(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to shut down admin server: %w", err))
+	}
+
+	if app.queue != nil {
+		app.logger.Info("Waiting for running queue jobs")
+		if err := app.queue.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop queue: %w", err))
+		}
+	}
+
+	app.mu.RLock()
+	hooks := append([]Hook(nil), app.shutdownHooks...)
+	app.mu.RUnlock()
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := withContext(ctx, func() error { return hooks[i](ctx) }); err != nil {
+			errs = append(errs, fmt.Errorf("shutdown hook failed: %w", err))
+		}
+	}
+
+	if app.plugins != nil {
+		if err := withContext(ctx, app.plugins.UnloadPlugins); err != nil {
+			errs = append(errs, fmt.Errorf("failed to unload plugins: %w", err))
+		}
+	}
+
+	for name, db := range app.databases {
+		if err := withContext(ctx, db.Close); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close database %s: %w", name, err))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		app.logger.Error("Shutdown finished with errors: %v", err)
+		return err
+	}
+
+	app.logger.Info("Shutdown complete")
+	return nil
+}
+
+func (app *Application) shutdownTimeout() time.Duration {
+	if app.config.Server.ShutdownTimeout > 0 {
+		return app.config.Server.ShutdownTimeout
+	}
+	return DefaultShutdownTimeout
+}
+
+// withContext runs fn but gives up waiting for it once ctx is done.
+func withContext(ctx context.Context, fn func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- fn()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,58 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShutdownRunsHooksInReverseOrder(t *testing.T) {
+	app := newTestApp(t, &Config{})
+
+	var calls []string
+	app.OnShutdown(func(ctx context.Context) error {
+		calls = append(calls, "first")
+		return errors.New("first failed")
+	})
+	app.OnShutdown(func(ctx context.Context) error {
+		calls = append(calls, "second")
+		return errors.New("second failed")
+	})
+
+	err := app.ShutdownWithContext(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, []string{"second", "first"}, calls)
+	assert.ErrorContains(t, err, "first failed")
+	assert.ErrorContains(t, err, "second failed")
+}
+
+func TestShutdownRespectsDeadline(t *testing.T) {
+	app := newTestApp(t, &Config{})
+
+	app.OnShutdown(func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := app.ShutdownWithContext(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestStartHookFailureAbortsStart(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	app.OnStart(func(ctx context.Context) error {
+		return errors.New("migrations pending")
+	})
+
+	err := app.Listen("127.0.0.1:0")
+	assert.ErrorContains(t, err, "migrations pending")
+}
@@ -0,0 +1,178 @@
+package forge
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ListenFDEnv carries the file descriptor of a listener handed over by
+	// the parent process during a binary upgrade.
+	ListenFDEnv = "FORGE_LISTEN_FD"
+
+	// upgradeReadyEnv carries the pipe an upgraded process closes once it
+	// is serving, telling the parent to shut down.
+	upgradeReadyEnv = "FORGE_UPGRADE_READY_FD"
+)
+
+// systemdFDStart is the first descriptor passed by systemd socket activation.
+var systemdFDStart = 3
+
+// Serve serves the application on ln, which may be any listener such as
+// one from net.Listen or a test harness. TLS is applied on top when
+// configured. Like Start, it runs the start hooks first and handles signals
+// when Server.HandleSignals is set.
+func (app *Application) Serve(ln net.Listener) error {
+	return app.run(func() error {
+		return app.serve(ln)
+	})
+}
+
+func (app *Application) serve(ln net.Listener) error {
+	go app.upgradeOnSIGUSR2(ln)
+
+	if app.config.Server.TLS.Enabled() {
+		tlsListener, err := app.tlsListener(ln)
+		if err != nil {
+			ln.Close()
+			return err
+		}
+		ln = tlsListener
+	}
+
+	notifyUpgradeReady()
+	return app.server.Listener(ln)
+}
+
+// prefork reports whether Fiber should open addr itself, since it only
+// preforks plain TCP listeners it created.
+func (app *Application) prefork(addr string) bool {
+	return app.config.Server.Prefork && !app.config.Server.TLS.Enabled() &&
+		!strings.HasPrefix(addr, "unix://") && !strings.HasPrefix(addr, "systemd") &&
+		os.Getenv(ListenFDEnv) == ""
+}
+
+// listen opens the listener for addr:
+//
+//	host:port              TCP
+//	unix:///run/app.sock   Unix socket
+//	systemd, systemd:name  socket passed by systemd socket activation
+//
+// A listener handed over by the parent process of a binary upgrade takes
+// precedence over addr.
+func (app *Application) listen(addr string) (net.Listener, error) {
+	if fd := os.Getenv(ListenFDEnv); fd != "" {
+		os.Unsetenv(ListenFDEnv)
+		n, err := strconv.Atoi(fd)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", ListenFDEnv, err)
+		}
+		return fileListener(n, "forge-listener")
+	}
+
+	switch {
+	case strings.HasPrefix(addr, "unix://"):
+		return app.listenUnix(strings.TrimPrefix(addr, "unix://"))
+	case addr == "systemd" || strings.HasPrefix(addr, "systemd:"):
+		return systemdListener(strings.TrimPrefix(strings.TrimPrefix(addr, "systemd"), ":"))
+	default:
+		network := app.server.Config().Network
+		if network == "" {
+			network = "tcp4"
+		}
+		return net.Listen(network, addr)
+	}
+}
+
+// listenUnix listens on a Unix socket, replacing a stale socket file left by
+// a previous run and applying Server.SocketMode.
+func (app *Application) listenUnix(path string) (net.Listener, error) {
+	if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("socket %s is in use by another process", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+		}
+	}
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	if mode := app.config.Server.SocketMode; mode != "" {
+		perm, err := strconv.ParseUint(mode, 8, 32)
+		if err != nil {
+			ln.Close()
+			return nil, fmt.Errorf("invalid socket_mode %q: %w", mode, err)
+		}
+		if err := os.Chmod(path, os.FileMode(perm)); err != nil {
+			ln.Close()
+			return nil, fmt.Errorf("failed to set socket permissions: %w", err)
+		}
+	}
+	return ln, nil
+}
+
+// systemdListener returns the socket systemd passed through LISTEN_FDS,
+// selecting it by LISTEN_FDNAMES when name is set.
+func systemdListener(name string) (net.Listener, error) {
+	if pid := os.Getenv("LISTEN_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return nil, errors.New("no sockets passed by systemd: LISTEN_PID belongs to another process")
+	}
+	count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || count < 1 {
+		return nil, errors.New("no sockets passed by systemd: LISTEN_FDS is not set")
+	}
+
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+	for i := 0; i < count; i++ {
+		fdName := ""
+		if i < len(names) {
+			fdName = names[i]
+		}
+		if name == "" || fdName == name {
+			return fileListener(systemdFDStart+i, "systemd:"+fdName)
+		}
+	}
+	return nil, fmt.Errorf("systemd did not pass a socket named %q", name)
+}
+
+func fileListener(fd int, name string) (net.Listener, error) {
+	f := os.NewFile(uintptr(fd), name)
+	if f == nil {
+		return nil, fmt.Errorf("invalid file descriptor %d", fd)
+	}
+	defer f.Close()
+
+	ln, err := net.FileListener(f)
+	if err != nil {
+		return nil, fmt.Errorf("file descriptor %d is not a listening socket: %w", fd, err)
+	}
+	return ln, nil
+}
+
+// notifyUpgradeReady tells the parent of a binary upgrade that this process
+// is serving, so the parent can shut down.
+func notifyUpgradeReady() {
+	fd := os.Getenv(upgradeReadyEnv)
+	if fd == "" {
+		return
+	}
+	os.Unsetenv(upgradeReadyEnv)
+
+	n, err := strconv.Atoi(fd)
+	if err != nil {
+		return
+	}
+	if f := os.NewFile(uintptr(n), "upgrade-ready"); f != nil {
+		f.Write([]byte{1})
+		f.Close()
+	}
+}
@@ -0,0 +1,155 @@
+//go:build !windows
+
+package forge
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pingApp(t *testing.T, config *Config) *Application {
+	app := newTestApp(t, config)
+	app.Get().Get("/ping", func(c *fiber.Ctx) error {
+		return c.SendString("pong")
+	})
+	return app
+}
+
+func getVia(t *testing.T, client *http.Client, url string) string {
+	t.Helper()
+	var resp *http.Response
+	var err error
+	require.Eventually(t, func() bool {
+		resp, err = client.Get(url)
+		return err == nil
+	}, 5*time.Second, 20*time.Millisecond, "server did not come up")
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+// dupListenerFD returns a new descriptor for ln, owned by the caller.
+func dupListenerFD(t *testing.T, ln net.Listener) int {
+	f, err := ln.(*net.TCPListener).File()
+	require.NoError(t, err)
+	defer f.Close()
+	fd, err := syscall.Dup(int(f.Fd()))
+	require.NoError(t, err)
+	return fd
+}
+
+func TestServeUsesGivenListener(t *testing.T) {
+	app := pingApp(t, &Config{})
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+	go func() { done <- app.Serve(ln) }()
+
+	assert.Equal(t, "pong", getVia(t, http.DefaultClient, "http://"+ln.Addr().String()+"/ping"))
+	require.NoError(t, app.ShutdownWithContext(context.Background()))
+	require.NoError(t, <-done)
+}
+
+func TestListenUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.sock")
+
+	// Leave a stale socket file behind, as a crashed process would.
+	stale, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	app := pingApp(t, &Config{Server: ServerConfig{Address: "unix://" + path, SocketMode: "0600"}})
+	done := make(chan error, 1)
+	go func() { done <- app.Start() }()
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", path)
+		},
+	}}
+	assert.Equal(t, "pong", getVia(t, client, "http://unix/ping"))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	require.NoError(t, app.ShutdownWithContext(context.Background()))
+	require.NoError(t, <-done)
+
+	again, err := app.listenUnix(path)
+	require.NoError(t, err, "socket path is reusable after shutdown")
+	again.Close()
+}
+
+func TestListenUnixSocketInUse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.sock")
+	ln, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	defer ln.Close()
+
+	app := newTestApp(t, &Config{})
+	_, err = app.listen("unix://" + path)
+	assert.ErrorContains(t, err, "in use")
+}
+
+func TestSystemdListener(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	start := systemdFDStart
+	t.Cleanup(func() { systemdFDStart = start })
+	systemdFDStart = dupListenerFD(t, ln) - 1
+
+	t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+	t.Setenv("LISTEN_FDS", "2")
+	t.Setenv("LISTEN_FDNAMES", "admin:web")
+
+	inherited, err := systemdListener("web")
+	require.NoError(t, err)
+	defer inherited.Close()
+	assert.Equal(t, ln.Addr().String(), inherited.Addr().String())
+
+	_, err = systemdListener("metrics")
+	assert.ErrorContains(t, err, `named "metrics"`)
+
+	t.Setenv("LISTEN_PID", "1")
+	_, err = systemdListener("web")
+	assert.ErrorContains(t, err, "another process")
+
+	t.Setenv("LISTEN_PID", "")
+	t.Setenv("LISTEN_FDS", "")
+	_, err = systemdListener("")
+	assert.ErrorContains(t, err, "LISTEN_FDS")
+}
+
+func TestListenPrefersUpgradeListener(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	t.Setenv(ListenFDEnv, strconv.Itoa(dupListenerFD(t, ln)))
+
+	app := newTestApp(t, &Config{})
+	inherited, err := app.listen("127.0.0.1:0")
+	require.NoError(t, err)
+	defer inherited.Close()
+
+	assert.Equal(t, ln.Addr().String(), inherited.Addr().String())
+	assert.Empty(t, os.Getenv(ListenFDEnv), "the handed over listener is only used once")
+}
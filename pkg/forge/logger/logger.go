@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -65,12 +66,15 @@ func ParseLevel(level string) Level {
 type Fields map[string]interface{}
 
 
+// Logger is safe for concurrent use. Loggers derived with WithField and
+// WithFields share the level and writer lock of their parent, so SetLevel
+// applies to all of them.
 type Logger struct {
-	level      Level
+	level      *atomic.Int32
 	writer     io.Writer
 	fields     Fields
 	timeFormat string
-	mu         sync.Mutex
+	mu         *sync.Mutex
 	colorized  bool
 }
 
@@ -100,11 +104,14 @@ func New(config Config) *Logger {
 	if config.TimeFormat == "" {
 		config.TimeFormat = "2006-01-02 15:04:05"
 	}
+	level := new(atomic.Int32)
+	level.Store(int32(config.Level))
 	return &Logger{
-		level:      config.Level,
+		level:      level,
 		writer:     config.Writer,
 		fields:     make(Fields),
 		timeFormat: config.TimeFormat,
+		mu:         new(sync.Mutex),
 		colorized:  config.Colorized,
 	}
 }
@@ -116,12 +123,24 @@ func DefaultLogger() *Logger {
 }
 
 
+// WithLevel returns a copy with its own level, unaffected by SetLevel on l.
 func (l *Logger) WithLevel(level Level) *Logger {
 	newLogger := *l
-	newLogger.level = level
+	newLogger.level = new(atomic.Int32)
+	newLogger.level.Store(int32(level))
 	return &newLogger
 }
 
+// Level returns the minimum level that is written.
+func (l *Logger) Level() Level {
+	return Level(l.level.Load())
+}
+
+// SetLevel changes the minimum level at runtime.
+func (l *Logger) SetLevel(level Level) {
+	l.level.Store(int32(level))
+}
+
 
 func (l *Logger) WithField(key string, value interface{}) *Logger {
 	newLogger := *l
@@ -174,7 +193,7 @@ func (l *Logger) Fatal(message string, args ...interface{}) {
 
 
 func (l *Logger) log(level Level, message string, args ...interface{}) {
-	if level < l.level {
+	if level < l.Level() {
 		return
 	}
 
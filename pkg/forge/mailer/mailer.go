@@ -2,9 +2,12 @@ package mailer
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"html/template"
+	"net"
 	"path/filepath"
+	"strconv"
 
 	"gopkg.in/mail.v2"
 )
@@ -16,12 +19,12 @@ type Mailer struct {
 }
 
 type Config struct {
-	Host     string
-	Port     int
-	Username string
-	Password string
-	From     string
-	TemplateDir string
+	Host        string `yaml:"host"`
+	Port        int    `yaml:"port"`
+	Username    string `yaml:"username"`
+	Password    string `yaml:"password" secret:"true"`
+	From        string `yaml:"from"`
+	TemplateDir string `yaml:"template_dir"`
 }
 
 func New(config Config) (*Mailer, error) {
@@ -46,6 +49,17 @@ func New(config Config) (*Mailer, error) {
 	}, nil
 }
 
+// CheckHealth verifies the SMTP server accepts connections without
+// authenticating, for the readiness endpoint.
+func (m *Mailer) CheckHealth(ctx context.Context) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(m.dialer.Host, strconv.Itoa(m.dialer.Port)))
+	if err != nil {
+		return fmt.Errorf("failed to reach SMTP server: %w", err)
+	}
+	return conn.Close()
+}
+
 func (m *Mailer) Send(to, subject, templateName string, data interface{}) error {
 	tmpl := m.templates.Lookup(templateName)
 	if tmpl == nil {
@@ -191,10 +191,16 @@ require (
 
 	// Create sample handler
 	handlerContent := generateSampleHandler(config)
-	if err := os.WriteFile(filepath.Join(name, "api", "handlers", "health.go"), []byte(handlerContent), 0644); err != nil {
+	if err := os.WriteFile(filepath.Join(name, "api", "handlers", "info.go"), []byte(handlerContent), 0644); err != nil {
 		return fmt.Errorf("failed to create sample handler: %w", err)
 	}
 
+	// Create the module bundling the service, so it can also be mounted into a monolith
+	moduleContent := generateModuleFile(config)
+	if err := os.WriteFile(filepath.Join(name, "api", "module.go"), []byte(moduleContent), 0644); err != nil {
+		return fmt.Errorf("failed to create module.go: %w", err)
+	}
+
 	// Create README.md
 	readmeContent := generateMicroserviceReadme(config)
 	if err := os.WriteFile(filepath.Join(name, "README.md"), []byte(readmeContent), 0644); err != nil {
@@ -211,16 +217,13 @@ func generateMicroserviceMainFile(config *MicroserviceConfig) string {
 import (
 	"fmt"
 	"log"
-	"os"
-	"os/signal"
-	"syscall"
 
 	"github.com/BisiOlaYemi/forge/pkg/forge"
+	"github.com/%s/api"
 )
 
 func main() {
-	// Create a new Forge application
-	app, err := forge.New(&forge.Config{
+	config := &forge.Config{
 		Name:        "%s",
 		Version:     "1.0.0",
 		Description: "%s",
@@ -228,52 +231,65 @@ func main() {
 			Host:     "0.0.0.0",
 			Port:     %d,
 			BasePath: "/api",
+			// Shut down gracefully on SIGINT/SIGTERM
+			HandleSignals: true,
 		},
 		%s
-	})
-	if err != nil {
-		log.Fatalf("Failed to create application: %%v", err)
 	}
 
-	// Configure API routes
-	app.Get().Get("/health", func(c *forge.Context) error {
-		return c.JSON(map[string]string{
-			"status":  "ok",
-			"service": "%s",
-			"version": "1.0.0",
-		})
-	})
-
-	// Register API handlers
-	// TODO: Add your handlers here
-
-	// Handle graceful shutdown
-	go func() {
-		quit := make(chan os.Signal, 1)
-		signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
-		<-quit
-		fmt.Println("Shutting down server...")
-		if err := app.Shutdown(); err != nil {
-			log.Fatalf("Error during shutdown: %%v", err)
-		}
-	}()
-
-	// Start the server
-	fmt.Printf("Server starting on http://0.0.0.0:%d/api\n", %d)
-	if err := app.Start(); err != nil {
+	// The service lives in api.Module(), which a monolith can mount with
+	// app.Mount("/%s", api.Module()) instead. RunModule serves it on its own,
+	// applying its migrations first. Liveness and readiness probes are served
+	// on /healthz and /readyz.
+	fmt.Println("Server starting on http://0.0.0.0:%d/api")
+	if err := forge.RunModule(config, api.Module()); err != nil {
 		log.Fatalf("Failed to start server: %%v", err)
 	}
 }
 `, 
+	config.Name, 
 	config.Name, 
 	config.Description, 
 	config.Port,
 	generateConfigOptions(config),
 	config.Name,
-	config.Port,
 	config.Port)
 }
 
+func generateModuleFile(config *MicroserviceConfig) string {
+	return `package api
+
+import (
+	"github.com/BisiOlaYemi/forge/pkg/forge"
+	"github.com/` + config.Name + `/api/handlers"
+)
+
+// Module returns the ` + config.Name + ` module. Add controllers, middleware,
+// migrations, queue handlers and health checks by implementing the optional
+// forge.Module* interfaces.
+func Module() forge.Module {
+	return &module{}
+}
+
+type module struct{}
+
+func (m *module) Name() string {
+	return "` + config.Name + `"
+}
+
+func (m *module) Controllers() []interface{} {
+	return []interface{}{
+		&handlers.InfoHandler{},
+	}
+}
+
+// Register anything the service cannot run without as a readiness check.
+func (m *module) HealthChecks() map[string]forge.HealthChecker {
+	return map[string]forge.HealthChecker{}
+}
+`
+}
+
 func generateConfigOptions(config *MicroserviceConfig) string {
 	options := ""
 	
@@ -313,6 +329,18 @@ server:
   read_timeout: 10s
   write_timeout: 10s
   idle_timeout: 120s
+  body_limit: 4194304
+  # trusted_proxies: ["10.0.0.0/8"]
+  # proxy_header: "X-Forwarded-For"
+  handle_signals: true
+  shutdown_timeout: 30s
+
+# Health Probes
+health:
+  liveness_path: "/healthz"
+  readiness_path: "/readyz"
+  timeout: 2s
+  cache_ttl: 5s
 
 %s
 `, 
@@ -329,18 +357,19 @@ func generateAdditionalConfig(config *MicroserviceConfig) string {
 	if config.WithDB {
 		additionalConfig += `# Database Configuration
 database:
-  driver: "sqlite"
-  name: "forge.db"
-  # For production:
-  # driver: "postgres"
-  # host: "db"
-  # port: 5432
-  # username: "postgres"
-  # password: "postgres"
-  # name: "forge"
-  max_open_conns: 20
-  max_idle_conns: 5
-  conn_max_life: 300s
+  default:
+    driver: "sqlite"
+    name: "forge.db"
+    # For production:
+    # driver: "postgres"
+    # host: "db"
+    # port: 5432
+    # username: "postgres"
+    # password: "${DB_PASSWORD}"  # or password_file: /run/secrets/db_password
+    # name: "forge"
+    max_open_conns: 20
+    max_idle_conns: 5
+    conn_max_life: 300s
 
 `
 	}
@@ -372,7 +401,7 @@ queue:
 		additionalConfig += `# Authentication Configuration
 auth:
   jwt:
-    secret: "change-this-to-a-secure-secret-in-production"
+    secret_key: "${JWT_SECRET:-change-this-to-a-secure-secret-in-production}"
     expiration: 86400s # 24 hours
     refresh_expiration: 604800s # 7 days
 
@@ -484,15 +513,14 @@ import (
 	"github.com/BisiOlaYemi/forge/pkg/forge"
 )
 
-// HealthHandler handles health check requests
-type HealthHandler struct {
+// InfoHandler describes the running service
+type InfoHandler struct {
 	forge.Controller
 }
 
-// HandleGetHealth handles GET /health requests
-func (h *HealthHandler) HandleGetHealth(ctx *forge.Context) error {
+// HandleGetInfo handles GET /info requests
+func (h *InfoHandler) HandleGetInfo(ctx *forge.Context) error {
 	return ctx.JSON(map[string]interface{}{
-		"status":  "ok",
 		"service": "` + config.Name + `",
 		"version": "1.0.0",
 	})
@@ -529,13 +557,15 @@ func generateMicroserviceReadme(config *MicroserviceConfig) string {
 
 ## API Endpoints
 
-- **Health Check**: GET /api/health
+- **Liveness**: GET /healthz
+- **Readiness**: GET /readyz (checks database, queue, mailer and plugins)
 
 ## Project Structure
 
 ` + "```" + `
 %s/
 ├── api/              # API layer
+│   ├── module.go     # forge.Module bundling the service
 │   ├── handlers/     # HTTP request handlers
 │   └── middleware/   # HTTP middleware
 ├── cmd/              # Application entry points
@@ -557,6 +587,9 @@ Configuration is managed through the ` + "```" + `config/config.yaml` + "```" +
 
 This service is built with the Forge Framework, which provides a modern Go web application architecture.
 
+The service is packaged as a module in ` + "```" + `api/module.go` + "```" + `. The same module can be mounted into a
+larger Forge application with ` + "```" + `app.Mount("/%s", api.Module())` + "```" + `.
+
 `, 
 	strings.ToTitle(config.Name),
 	config.Description,
@@ -564,5 +597,6 @@ This service is built with the Forge Framework, which provides a modern Go web a
 	config.Port,
 	config.Port,
 	config.Name,
+	config.Name,
 	config.Name)
 }
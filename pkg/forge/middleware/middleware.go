@@ -91,6 +91,19 @@ func RequireAuth() forge.MiddlewareFunc {
 	}
 }
 
+// RequireFeature responds 404 unless the named feature flag is on for the
+// current user, so gated routes look like they do not exist.
+func RequireFeature(name string) forge.MiddlewareFunc {
+	return func(next forge.HandlerFunc) forge.HandlerFunc {
+		return func(ctx *forge.Context) error {
+			if !ctx.FeatureEnabled(name) {
+				return forge.ErrNotFound
+			}
+			return next(ctx)
+		}
+	}
+}
+
 //  CORS headers
 func CORS(options forge.CORSConfig) forge.MiddlewareFunc {
 	return func(next forge.HandlerFunc) forge.HandlerFunc {
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/BisiOlaYemi/forge/pkg/forge"
+	"github.com/BisiOlaYemi/forge/pkg/forge/flags"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireFeature(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	app, err := forge.New(&forge.Config{
+		CORS:  forge.CORSConfig{AllowOrigins: "http://localhost"},
+		Flags: forge.FlagsConfig{Flags: map[string]flags.Flag{"beta": {Enabled: true, Users: []string{"1"}}}},
+	})
+	require.NoError(t, err)
+
+	handler := RequireFeature("beta")(func(ctx *forge.Context) error {
+		return ctx.SendString("beta")
+	})
+	server := fiber.New(fiber.Config{ErrorHandler: func(c *fiber.Ctx, err error) error {
+		return c.SendStatus(forge.AsAppError(err).StatusCode)
+	}})
+	server.Get("/beta", func(c *fiber.Ctx) error {
+		c.Locals("user_id", c.Query("user"))
+		return handler(forge.NewContext(c, app))
+	})
+
+	for user, status := range map[string]int{"1": http.StatusOK, "2": http.StatusNotFound} {
+		resp, err := server.Test(httptest.NewRequest(http.MethodGet, "/beta?user="+user, nil))
+		require.NoError(t, err)
+		assert.Equal(t, status, resp.StatusCode, "user %s", user)
+	}
+}
+
+func TestRequireAuthOnGroup(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	app, err := forge.New(&forge.Config{CORS: forge.CORSConfig{AllowOrigins: "http://localhost"}})
+	require.NoError(t, err)
+	app.Use(RequestLogger())
+	app.Group("/private").Use(RequireAuth()).Get("/me", func(ctx *forge.Context) error {
+		return ctx.SendString("me")
+	})
+	app.Group("/public").Get("/ping", func(ctx *forge.Context) error {
+		return ctx.SendString("pong")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/private/me", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/public/ping", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
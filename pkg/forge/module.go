@@ -0,0 +1,190 @@
+package forge
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/BisiOlaYemi/forge/pkg/forge/queue"
+	"gopkg.in/yaml.v3"
+)
+
+// Module bundles a feature area, such as billing or identity, so it can be
+// mounted into an Application with Mount or run on its own with RunModule.
+// A module contributes through the optional interfaces below.
+type Module interface {
+	Name() string
+}
+
+// ModuleInitializer is called when the module is mounted, after its config
+// section is loaded and before its controllers are registered, e.g. to
+// Provide the module's services.
+type ModuleInitializer interface {
+	Init(app *Application) error
+}
+
+// ModuleControllers lists the controllers mounted under the module prefix.
+type ModuleControllers interface {
+	Controllers() []interface{}
+}
+
+// ModuleMiddleware lists middleware wrapping every controller of the module.
+type ModuleMiddleware interface {
+	Middleware() []MiddlewareFunc
+}
+
+// ModuleMigrations lists migrations applied by Application.Migrate. Names
+// are prefixed with the module name.
+type ModuleMigrations interface {
+	Migrations() []Migration
+}
+
+// ModuleQueueHandlers maps job types to the handlers processing them.
+type ModuleQueueHandlers interface {
+	QueueHandlers() map[string]queue.Handler
+}
+
+// ModuleConfig returns a pointer that is filled from the modules.<name>
+// section of the configuration and FORGE_MODULES_<NAME>_* variables.
+type ModuleConfig interface {
+	ConfigSection() interface{}
+}
+
+// ModuleHealthChecks lists readiness checks, registered as <module>:<name>.
+type ModuleHealthChecks interface {
+	HealthChecks() map[string]HealthChecker
+}
+
+// Mount mounts module under prefix: controllers are served below the base
+// path and prefix, and migrations, queue handlers and health checks are
+// registered with the application.
+func (app *Application) Mount(prefix string, module Module) error {
+	name := module.Name()
+
+	app.mu.Lock()
+	for _, mounted := range app.modules {
+		if mounted == name {
+			app.mu.Unlock()
+			return fmt.Errorf("module %s is already mounted", name)
+		}
+	}
+	app.modules = append(app.modules, name)
+	app.mu.Unlock()
+
+	if m, ok := module.(ModuleConfig); ok {
+		if err := app.config.decodeModuleConfig(name, m.ConfigSection()); err != nil {
+			return fmt.Errorf("module %s: %w", name, err)
+		}
+	}
+
+	if m, ok := module.(ModuleInitializer); ok {
+		if err := m.Init(app); err != nil {
+			return fmt.Errorf("module %s: %w", name, err)
+		}
+	}
+
+	var middleware []MiddlewareFunc
+	if m, ok := module.(ModuleMiddleware); ok {
+		middleware = m.Middleware()
+	}
+
+	if m, ok := module.(ModuleControllers); ok {
+		for _, controller := range m.Controllers() {
+			entry := controllerEntry{prefix: prefix, module: name, version: controllerVersion(controller), middleware: middleware}
+			if err := app.registerController(controller, entry); err != nil {
+				return fmt.Errorf("module %s: %w", name, err)
+			}
+		}
+	}
+
+	if m, ok := module.(ModuleMigrations); ok {
+		app.mu.Lock()
+		for _, migration := range m.Migrations() {
+			migration.Name = name + "/" + migration.Name
+			app.migrations = append(app.migrations, migration)
+		}
+		app.mu.Unlock()
+	}
+
+	if m, ok := module.(ModuleQueueHandlers); ok {
+		handlers := m.QueueHandlers()
+		if app.queue == nil && len(handlers) > 0 {
+			app.logger.Warn("Module %s has queue handlers but no queue is configured", name)
+		}
+		for jobType, handler := range handlers {
+			if app.queue != nil {
+				app.queue.RegisterHandler(jobType, handler)
+			}
+		}
+	}
+
+	if m, ok := module.(ModuleHealthChecks); ok {
+		for check, checker := range m.HealthChecks() {
+			app.AddHealthCheck(name+":"+check, checker)
+		}
+	}
+
+	app.logger.Info("Mounted module %s at %s", name, joinPath(app.config.Server.BasePath, prefix))
+	return nil
+}
+
+// Modules returns the names of the mounted modules in mount order.
+func (app *Application) Modules() []string {
+	app.mu.RLock()
+	defer app.mu.RUnlock()
+	return append([]string(nil), app.modules...)
+}
+
+// Migrate applies the migrations of every mounted module to the default
+// database.
+func (app *Application) Migrate() error {
+	migrator, err := app.Migrator(DefaultDatabase)
+	if err != nil {
+		return err
+	}
+	return migrator.Migrate()
+}
+
+// RunModule runs a module as its own service: it creates an Application
+// from config, mounts the module at the root, applies its migrations and
+// starts serving.
+func RunModule(config *Config, module Module) error {
+	app, err := New(config)
+	if err != nil {
+		return err
+	}
+	if err := app.Mount("/", module); err != nil {
+		return err
+	}
+
+	app.mu.RLock()
+	pending := len(app.migrations)
+	app.mu.RUnlock()
+	if pending > 0 {
+		if err := app.Migrate(); err != nil {
+			return err
+		}
+	}
+	return app.Start()
+}
+
+// decodeModuleConfig fills target from the module's config section and its
+// FORGE_MODULES_<NAME>_* environment overrides.
+func (c *Config) decodeModuleConfig(name string, target interface{}) error {
+	if section, ok := c.Modules[name]; ok {
+		data, err := yaml.Marshal(section)
+		if err != nil {
+			return fmt.Errorf("failed to read config section: %w", err)
+		}
+		if err := yaml.Unmarshal(data, target); err != nil {
+			return fmt.Errorf("failed to decode config section: %w", err)
+		}
+	}
+
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	key := envPrefix + "_MODULES_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+	return applyEnvOverrides(v.Elem(), key)
+}
@@ -0,0 +1,98 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type billingConfig struct {
+	Currency string `yaml:"currency"`
+	TaxRate  int    `yaml:"tax_rate"`
+}
+
+type InvoiceController struct {
+	Controller
+	Config *billingConfig `inject:""`
+}
+
+func (c *InvoiceController) HandleGetInvoices(ctx *Context) error {
+	return ctx.SendString(c.Config.Currency + " " + ctx.Get("X-Billing"))
+}
+
+type billingModule struct {
+	config billingConfig
+}
+
+func (m *billingModule) Name() string { return "billing" }
+
+func (m *billingModule) ConfigSection() interface{} { return &m.config }
+
+func (m *billingModule) Init(app *Application) error {
+	return app.Provide(&m.config)
+}
+
+func (m *billingModule) Controllers() []interface{} {
+	return []interface{}{&InvoiceController{}}
+}
+
+func (m *billingModule) Middleware() []MiddlewareFunc {
+	return []MiddlewareFunc{func(next HandlerFunc) HandlerFunc {
+		return func(ctx *Context) error {
+			ctx.Request().Header.Set("X-Billing", "checked")
+			return next(ctx)
+		}
+	}}
+}
+
+func (m *billingModule) Migrations() []Migration {
+	return []Migration{{
+		Name: "create_invoices",
+		Up:   func(db *gorm.DB) error { return db.Exec("CREATE TABLE invoices (id INTEGER)").Error },
+		Down: func(db *gorm.DB) error { return db.Exec("DROP TABLE invoices").Error },
+	}}
+}
+
+func (m *billingModule) HealthChecks() map[string]HealthChecker {
+	return map[string]HealthChecker{
+		"gateway": HealthCheckFunc(func(ctx context.Context) error { return errors.New("unreachable") }),
+	}
+}
+
+func TestMountModule(t *testing.T) {
+	t.Setenv("FORGE_MODULES_BILLING_TAX_RATE", "20")
+	app := newTestApp(t, &Config{
+		Server:   ServerConfig{BasePath: "/api"},
+		Database: DatabaseConfig{Driver: "sqlite", Name: "modules.db"},
+		Modules:  map[string]interface{}{"billing": map[string]interface{}{"currency": "EUR"}},
+	})
+
+	module := &billingModule{}
+	require.NoError(t, app.Mount("/billing", module))
+	assert.Equal(t, billingConfig{Currency: "EUR", TaxRate: 20}, module.config)
+	assert.Equal(t, []string{"billing"}, app.Modules())
+
+	status, body := getBody(t, app, "/api/billing/invoice/invoices")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "EUR checked", body)
+
+	route := findRoute(app.Routes(), "GET", "/api/billing/invoice/invoices")
+	require.NotNil(t, route)
+	assert.Equal(t, "billing", route.Module)
+	assert.Len(t, route.Middleware, 4)
+
+	report := app.Health(context.Background())
+	assert.Equal(t, "unreachable", report.Checks["billing:gateway"].Error)
+
+	require.NoError(t, app.Migrate())
+	var applied []string
+	require.NoError(t, app.DB().Raw("SELECT name FROM migrations").Scan(&applied).Error)
+	assert.Equal(t, []string{"billing/create_invoices"}, applied)
+
+	assert.ErrorContains(t, app.Mount("/other", &billingModule{}), "module billing is already mounted")
+}
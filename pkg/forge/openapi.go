@@ -3,8 +3,12 @@ package forge
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"reflect"
+	"strconv"
 	"strings"
+
+	"github.com/gofiber/fiber/v2"
 )
 
 
@@ -97,39 +101,26 @@ type SecurityScheme struct {
 	BearerFormat string `json:"bearerFormat,omitempty"`
 }
 
-func getMethodAnnotations(methodName string) []string {
-	annotations := make([]string, 0)
-	for _, httpMethod := range []string{"Get", "Post", "Put", "Delete", "Patch"} {
-		if strings.HasPrefix(methodName, httpMethod) {
-			annotations = append(annotations, httpMethod)
-		}
-	}
-	return annotations
+func (app *Application) GenerateOpenAPI() (*OpenAPISpec, error) {
+	return app.buildOpenAPI(app.config.Version, func(controllerEntry) bool {
+		return true
+	})
 }
 
-func getHTTPMethodFromAnnotations(method reflect.Method) string {
-	if method.PkgPath != "" {
-		return "" 
-	}
-
-	annotations := getMethodAnnotations(method.Name)
-	for _, annotation := range annotations {
-		httpMethod := strings.ToUpper(annotation)
-		if httpMethod != "" {
-			return httpMethod
-		}
-	}
-	return ""
+// generateOpenAPI builds the document for a single API version.
+func (app *Application) generateOpenAPI(version string) (*OpenAPISpec, error) {
+	return app.buildOpenAPI(version, func(entry controllerEntry) bool {
+		return entry.version == version
+	})
 }
 
-
-func (app *Application) GenerateOpenAPI() (*OpenAPISpec, error) {
+func (app *Application) buildOpenAPI(version string, include func(controllerEntry) bool) (*OpenAPISpec, error) {
 	spec := &OpenAPISpec{
 		OpenAPI: "3.0.0",
 		Info: OpenAPIInfo{
 			Title:       app.config.Name,
 			Description: app.config.Description,
-			Version:     app.config.Version,
+			Version:     version,
 		},
 		Paths: make(map[string]PathItem),
 		Components: OpenAPIComponents{
@@ -147,26 +138,41 @@ func (app *Application) GenerateOpenAPI() (*OpenAPISpec, error) {
 	}
 
 	
+	responseTypes := app.rendererTypes(false)
+	requestTypes := app.rendererTypes(true)
+
 	app.mu.RLock()
 	defer app.mu.RUnlock()
 
-	for _, controller := range app.controllers {
-		controllerType := reflect.TypeOf(controller)
-		for i := 0; i < controllerType.NumMethod(); i++ {
-			method := controllerType.Method(i)
-			httpMethod := getHTTPMethodFromAnnotations(method)
-			if httpMethod == "" {
+	operationIDs := make(map[string]bool)
+	for _, entry := range app.controllers {
+		if !include(entry) {
+			continue
+		}
+
+		controllerName := reflect.TypeOf(entry.controller).Elem().Name()
+		for _, route := range entry.routes {
+			if route.websocket {
 				continue
 			}
-
-			
-			path := fmt.Sprintf("/%s/%s", strings.ToLower(controllerType.Name()), strings.ToLower(method.Name))
+			httpMethod := route.HTTPMethod
+			method := route.method
+			path, parameters := openAPIPath(route.Path)
+
+			// A handler served on several routes, like a resource's Update on
+			// PUT and PATCH, needs distinct operation IDs.
+			operationID := fmt.Sprintf("%s_%s", strings.ToLower(controllerName), strings.ToLower(method.Name))
+			if operationIDs[operationID] {
+				operationID += "_" + strings.ToLower(httpMethod)
+			}
+			operationIDs[operationID] = true
 
 			
 			operation := &Operation{
 				Summary:     fmt.Sprintf("%s %s", httpMethod, path),
-				OperationID: fmt.Sprintf("%s_%s", strings.ToLower(controllerType.Name()), strings.ToLower(method.Name)),
-				Parameters:  make([]*Parameter, 0),
+				OperationID: operationID,
+				Parameters:  parameters,
+				Tags:        []string{strings.TrimSuffix(controllerName, "Controller")},
 				Responses: map[string]*Response{
 					"200": {
 						Description: "Successful operation",
@@ -186,16 +192,54 @@ func (app *Application) GenerateOpenAPI() (*OpenAPISpec, error) {
 			}
 
 			
+			if sig, err := parseHandlerSignature(method); err == nil {
+				if sig.request != nil {
+					for _, parameter := range requestParameters(sig.request) {
+						operation.Parameters = mergeParameter(operation.Parameters, parameter)
+					}
+					operation.Responses["400"] = &Response{Description: "Validation error"}
+				}
+				if sig.response != nil {
+					status := http.StatusOK
+					if httpMethod == "POST" {
+						status = http.StatusCreated
+					}
+					delete(operation.Responses, "200")
+					operation.Responses[strconv.Itoa(status)] = &Response{
+						Description: http.StatusText(status),
+						Content:     mediaTypeContent(responseTypes, generateSchemaFromType(sig.response)),
+					}
+					operation.Responses["406"] = &Response{Description: http.StatusText(http.StatusNotAcceptable)}
+				}
+			}
+
+			if meta := route.meta; meta != nil {
+				operation.Description = meta.Description
+				if meta.Response != nil {
+					operation.Responses["200"] = jsonResponse(http.StatusOK, reflect.TypeOf(meta.Response))
+				}
+				for status, response := range meta.Responses {
+					documented := &Response{Description: http.StatusText(status)}
+					if response != nil {
+						documented = jsonResponse(status, reflect.TypeOf(response))
+					}
+					operation.Responses[strconv.Itoa(status)] = documented
+				}
+				operation.Parameters = withRouteParams(operation.Parameters, meta.Params)
+			}
+
 			if httpMethod == "POST" || httpMethod == "PUT" || httpMethod == "PATCH" {
-				requestType := getRequestTypeFromMethod(method)
-				if requestType != nil {
+				var body *Schema
+				if requestType := getRequestTypeFromMethod(method); requestType != nil {
+					body = requestBodySchema(requestType)
+				}
+				if route.meta != nil && route.meta.RequestBody != nil {
+					body = generateSchemaFromType(reflect.TypeOf(route.meta.RequestBody))
+				}
+				if body != nil {
 					operation.RequestBody = &RequestBody{
 						Required: true,
-						Content: map[string]MediaTypeObject{
-							"application/json": {
-								Schema: generateSchemaFromType(requestType),
-							},
-						},
+						Content:  mediaTypeContent(requestTypes, body),
 					}
 				}
 			}
@@ -226,6 +270,153 @@ func (app *Application) GenerateOpenAPI() (*OpenAPISpec, error) {
 	return spec, nil
 }
 
+// openAPIPath converts a Fiber path such as /users/:id into /users/{id} and
+// returns the matching path parameters.
+func openAPIPath(path string) (string, []*Parameter) {
+	parameters := make([]*Parameter, 0)
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, ":") {
+			continue
+		}
+
+		name := strings.TrimSuffix(strings.TrimPrefix(segment, ":"), "?")
+		segments[i] = "{" + name + "}"
+		parameters = append(parameters, &Parameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+	return strings.Join(segments, "/"), parameters
+}
+
+// withRouteParams documents params, completing the path parameters derived
+// from the route.
+func withRouteParams(parameters []*Parameter, params []RouteParam) []*Parameter {
+	for _, param := range params {
+		parameter := &Parameter{Name: param.Name, In: param.In, Description: param.Description, Required: param.Required}
+		if param.Type != "" {
+			parameter.Schema = &Schema{Type: param.Type}
+		}
+		parameters = mergeParameter(parameters, parameter)
+	}
+	return parameters
+}
+
+// mergeParameter adds parameter, or completes the one with the same name and
+// location.
+func mergeParameter(parameters []*Parameter, parameter *Parameter) []*Parameter {
+	for _, existing := range parameters {
+		if existing.Name != parameter.Name || existing.In != parameter.In {
+			continue
+		}
+		if parameter.Description != "" {
+			existing.Description = parameter.Description
+		}
+		existing.Required = existing.Required || parameter.Required
+		if parameter.Schema != nil {
+			existing.Schema = parameter.Schema
+		}
+		return parameters
+	}
+
+	if parameter.In == "path" {
+		parameter.Required = true
+	}
+	if parameter.Schema == nil {
+		parameter.Schema = &Schema{Type: "string"}
+	}
+	return append(parameters, parameter)
+}
+
+// requestParameters documents the fields of a typed handler's request that
+// are bound from the path, query or headers.
+func requestParameters(t reflect.Type) []*Parameter {
+	var parameters []*Parameter
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			parameters = append(parameters, requestParameters(field.Type)...)
+			continue
+		}
+		for _, in := range []string{"path", "query", "header"} {
+			if name := field.Tag.Get(in); name != "" {
+				parameters = append(parameters, &Parameter{
+					Name:     name,
+					In:       in,
+					Required: in == "path" || strings.Contains(field.Tag.Get("validate"), "required"),
+					Schema:   generateSchemaFromType(field.Type),
+				})
+			}
+		}
+	}
+	return parameters
+}
+
+// requestBodySchema describes the body of a request type, leaving out the
+// fields bound from the path, query or headers. It returns nil when no
+// field is left.
+func requestBodySchema(t reflect.Type) *Schema {
+	schema := generateSchemaFromType(t)
+	if schema == nil || schema.Type != "object" {
+		return schema
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	for _, parameter := range requestParameters(t) {
+		field, ok := requestField(t, parameter.In, parameter.Name)
+		if !ok {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" {
+			name = field.Name
+		}
+		delete(schema.Properties, name)
+		for i, required := range schema.Required {
+			if required == name {
+				schema.Required = append(schema.Required[:i], schema.Required[i+1:]...)
+				break
+			}
+		}
+	}
+	if len(schema.Properties) == 0 {
+		return nil
+	}
+	return schema
+}
+
+func requestField(t reflect.Type, in, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.Tag.Get(in) == name {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// mediaTypeContent describes the same schema in each of mediaTypes.
+func mediaTypeContent(mediaTypes []string, schema *Schema) map[string]MediaTypeObject {
+	content := make(map[string]MediaTypeObject)
+	for _, mediaType := range mediaTypes {
+		content[mediaType] = MediaTypeObject{Schema: schema}
+	}
+	return content
+}
+
+func jsonResponse(status int, t reflect.Type) *Response {
+	return &Response{
+		Description: http.StatusText(status),
+		Content: map[string]MediaTypeObject{
+			"application/json": {Schema: generateSchemaFromType(t)},
+		},
+	}
+}
+
 
 func getRequestTypeFromMethod(method reflect.Method) reflect.Type {
 	methodType := method.Type
@@ -236,6 +427,37 @@ func getRequestTypeFromMethod(method reflect.Method) reflect.Type {
 }
 
 
+func (app *Application) handleOpenAPISpec(c *fiber.Ctx) error {
+	spec, err := app.requestedOpenAPI(c)
+	if err != nil {
+		return err
+	}
+	return c.JSON(spec)
+}
+
+func (app *Application) handleSwaggerUI(c *fiber.Ctx) error {
+	spec, err := app.requestedOpenAPI(c)
+	if err != nil {
+		return err
+	}
+
+	html, err := GenerateSwaggerUI(spec)
+	if err != nil {
+		return err
+	}
+	return c.Type("html").SendString(html)
+}
+
+
+// requestedOpenAPI returns the document for ?version=v1, or the full document.
+func (app *Application) requestedOpenAPI(c *fiber.Ctx) (*OpenAPISpec, error) {
+	if version := c.Query("version"); version != "" {
+		return app.Version(version).GenerateOpenAPI()
+	}
+	return app.GenerateOpenAPI()
+}
+
+
 func GenerateSwaggerUI(spec *OpenAPISpec) (string, error) {
 	specJSON, err := json.Marshal(spec)
 	if err != nil {
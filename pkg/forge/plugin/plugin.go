@@ -2,6 +2,7 @@ package plugin
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -15,7 +16,7 @@ type AppInterface interface {
 	GetAuth() interface{}
 	GetQueue() interface{}
 	GetMailer() interface{}
-	RegisterController(controller interface{})
+	RegisterController(controller interface{}) error
 }
 
 type Plugin interface {
@@ -28,6 +29,7 @@ type Plugin interface {
 
 type Manager struct {
 	plugins     map[string]Plugin
+	order       []string
 	app         AppInterface
 	mu          sync.RWMutex
 	pluginDir   string
@@ -98,24 +100,29 @@ func (m *Manager) LoadPlugins() error {
 		}
 
 		m.plugins[plugin.Name()] = plugin
+		m.order = append(m.order, plugin.Name())
 
 		return nil
 	})
 }
 
-// UnloadPlugins unloads all plugins
+// UnloadPlugins shuts plugins down in reverse load order. Every plugin is
+// shut down even if an earlier one fails; the failures are returned joined.
 func (m *Manager) UnloadPlugins() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	for name, plugin := range m.plugins {
-		if err := plugin.Shutdown(); err != nil {
-			return fmt.Errorf("failed to shutdown plugin %s: %w", name, err)
+	var errs []error
+	for i := len(m.order) - 1; i >= 0; i-- {
+		name := m.order[i]
+		if err := m.plugins[name].Shutdown(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to shutdown plugin %s: %w", name, err))
 		}
 		delete(m.plugins, name)
 	}
+	m.order = nil
 
-	return nil
+	return errors.Join(errs...)
 }
 
 // GetPlugin returns a plugin by name
@@ -127,6 +134,18 @@ func (m *Manager) GetPlugin(name string) (Plugin, bool) {
 	return plugin, ok
 }
 
+// List returns the loaded plugins in load order
+func (m *Manager) List() []Plugin {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	plugins := make([]Plugin, 0, len(m.order))
+	for _, name := range m.order {
+		plugins = append(plugins, m.plugins[name])
+	}
+	return plugins
+}
+
 // loadConfig loads the plugin configuration
 func (m *Manager) loadConfig() (map[string]Config, error) {
 	configPath := filepath.Join(m.pluginDir, "config.json")
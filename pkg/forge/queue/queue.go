@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -14,12 +17,27 @@ type Queue struct {
 	handlers map[string]Handler
 	ctx      context.Context
 	cancel   context.CancelFunc
+	stopping chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+
+	processed atomic.Int64
+	failed    atomic.Int64
+}
+
+// Stats describes the queue for diagnostics. Processed and Failed count jobs
+// handled by this process since it started.
+type Stats struct {
+	Pending   int64    `json:"pending"`
+	Processed int64    `json:"processed"`
+	Failed    int64    `json:"failed"`
+	Handlers  []string `json:"handlers"`
 }
 
 type Config struct {
 	Host     string `yaml:"host"`
 	Port     int    `yaml:"port"`
-	Password string `yaml:"password"`
+	Password string `yaml:"password" secret:"true"`
 	DB       int    `yaml:"db"`
 }
 
@@ -48,6 +66,7 @@ func New(host string, password string, db int) (*Queue, error) {
 		handlers: make(map[string]Handler),
 		ctx:      ctx,
 		cancel:   cancel,
+		stopping: make(chan struct{}),
 	}, nil
 }
 
@@ -83,23 +102,78 @@ func (q *Queue) Enqueue(jobType string, data map[string]interface{}, maxRetries
 }
 
 func (q *Queue) Start() {
+	q.done = make(chan struct{})
 	go q.processJobs()
 }
 
+// Stop cancels the worker immediately, interrupting any running job.
 func (q *Queue) Stop() {
 	q.cancel()
 }
 
+// Shutdown stops picking up new jobs and waits for the running job to finish.
+// If ctx expires first the worker is cancelled and ctx.Err() is returned.
+func (q *Queue) Shutdown(ctx context.Context) error {
+	q.stopOnce.Do(func() {
+		close(q.stopping)
+	})
+
+	if q.done != nil {
+		select {
+		case <-q.done:
+		case <-ctx.Done():
+			q.cancel()
+			return ctx.Err()
+		}
+	}
+
+	q.cancel()
+	return q.client.Close()
+}
+
+// CheckHealth pings Redis for the readiness endpoint.
+func (q *Queue) CheckHealth(ctx context.Context) error {
+	return q.client.Ping(ctx).Err()
+}
+
+// Stats returns the number of pending jobs and the counters of this worker.
+func (q *Queue) Stats(ctx context.Context) (Stats, error) {
+	stats := Stats{
+		Processed: q.processed.Load(),
+		Failed:    q.failed.Load(),
+		Handlers:  make([]string, 0, len(q.handlers)),
+	}
+	for jobType := range q.handlers {
+		stats.Handlers = append(stats.Handlers, jobType)
+	}
+	sort.Strings(stats.Handlers)
+
+	pending, err := q.client.LLen(ctx, "queue").Result()
+	if err != nil {
+		return stats, err
+	}
+	stats.Pending = pending
+	return stats, nil
+}
+
 func (q *Queue) processJobs() {
+	defer close(q.done)
+
 	for {
 		select {
 		case <-q.ctx.Done():
 			return
+		case <-q.stopping:
+			return
 		default:
 			jobID, err := q.client.RPop(q.ctx, "queue").Result()
 			if err != nil {
 				if err == redis.Nil {
-					time.Sleep(time.Second)
+					select {
+					case <-time.After(time.Second):
+					case <-q.stopping:
+					case <-q.ctx.Done():
+					}
 					continue
 				}
 				continue
@@ -117,7 +191,9 @@ func (q *Queue) processJobs() {
 			}
 
 			if handler, ok := q.handlers[job.Type]; ok {
+				q.processed.Add(1)
 				if err := handler(&job); err != nil {
+					q.failed.Add(1)
 					job.Attempts++
 					if job.Attempts < job.MaxRetries {
 						q.client.LPush(q.ctx, "queue", job.ID)
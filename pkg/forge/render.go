@@ -0,0 +1,311 @@
+package forge
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Renderer writes response data in one media type. Renderers are
+// registered with Application.RegisterRenderer and picked by Context.Render.
+type Renderer interface {
+	Render(w io.Writer, data interface{}) error
+}
+
+// RequestDecoder is implemented by renderers that also read request bodies
+// of their media type, making the type available to Context.Bind.
+type RequestDecoder interface {
+	Decode(body []byte, v interface{}) error
+}
+
+// RendererFunc adapts a function to a Renderer.
+type RendererFunc func(w io.Writer, data interface{}) error
+
+func (f RendererFunc) Render(w io.Writer, data interface{}) error {
+	return f(w, data)
+}
+
+type registeredRenderer struct {
+	mediaType string
+	renderer  Renderer
+}
+
+// RegisterRenderer makes Context.Render answer requests accepting mediaType
+// with renderer, replacing any renderer registered for it before. When the
+// client accepts several types equally, the one registered first wins.
+// Only application/json is registered by default; XML and CSV are opt-in:
+//
+//	app.RegisterRenderer(fiber.MIMEApplicationXML, forge.XMLRenderer{})
+//	app.RegisterRenderer("text/csv", forge.CSVRenderer{})
+func (app *Application) RegisterRenderer(mediaType string, renderer Renderer) {
+	mediaType = strings.ToLower(mediaType)
+
+	app.renderersMu.Lock()
+	defer app.renderersMu.Unlock()
+	for i, registered := range app.renderers {
+		if registered.mediaType == mediaType {
+			app.renderers[i].renderer = renderer
+			return
+		}
+	}
+	app.renderers = append(app.renderers, registeredRenderer{mediaType: mediaType, renderer: renderer})
+}
+
+func (app *Application) registerDefaultRenderers() {
+	app.RegisterRenderer(fiber.MIMEApplicationJSON, jsonRenderer{})
+}
+
+// rendererTypes lists the registered media types in order of preference,
+// only those that decode requests if decoders is set.
+func (app *Application) rendererTypes(decoders bool) []string {
+	app.renderersMu.RLock()
+	defer app.renderersMu.RUnlock()
+
+	var types []string
+	for _, registered := range app.renderers {
+		if _, ok := registered.renderer.(RequestDecoder); ok || !decoders {
+			types = append(types, registered.mediaType)
+		}
+	}
+	return types
+}
+
+func (app *Application) renderer(mediaType string) Renderer {
+	app.renderersMu.RLock()
+	defer app.renderersMu.RUnlock()
+
+	for _, registered := range app.renderers {
+		if registered.mediaType == mediaType {
+			return registered.renderer
+		}
+	}
+	return nil
+}
+
+// Render writes data with status in the media type the client prefers
+// among the registered renderers, according to its Accept header. It
+// returns ErrNotAcceptable if the client accepts none of them.
+func (c *Context) Render(status int, data interface{}) error {
+	if c.app == nil {
+		return c.Status(status).JSON(data)
+	}
+
+	offers := c.app.rendererTypes(false)
+	c.Vary(fiber.HeaderAccept)
+	mediaType := c.Accepts(offers...)
+	if mediaType == "" {
+		return ErrNotAcceptable.WithDetails(map[string]interface{}{"supported": offers})
+	}
+
+	var body bytes.Buffer
+	if err := c.app.renderer(mediaType).Render(&body, data); err != nil {
+		return err
+	}
+	c.Ctx.Status(status)
+	c.Set(fiber.HeaderContentType, contentType(mediaType))
+	return c.Send(body.Bytes())
+}
+
+// contentType adds a charset to the text media types.
+func contentType(mediaType string) string {
+	if strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "/xml") {
+		return mediaType + "; charset=utf-8"
+	}
+	return mediaType
+}
+
+// requestDecoder returns the decoder registered for the request's content
+// type, if any.
+func (c *Context) requestDecoder() RequestDecoder {
+	if c.app == nil {
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(string(c.Request().Header.ContentType()))
+	if err != nil {
+		return nil
+	}
+	decoder, _ := c.app.renderer(mediaType).(RequestDecoder)
+	return decoder
+}
+
+type jsonRenderer struct{}
+
+func (jsonRenderer) Render(w io.Writer, data interface{}) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(body)
+	return err
+}
+
+func (jsonRenderer) Decode(body []byte, v interface{}) error {
+	return json.Unmarshal(body, v)
+}
+
+// XMLRenderer renders and decodes application/xml with encoding/xml,
+// which cannot encode maps.
+type XMLRenderer struct{}
+
+func (XMLRenderer) Render(w io.Writer, data interface{}) error {
+	body, err := xml.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	_, err = w.Write(body)
+	return err
+}
+
+func (XMLRenderer) Decode(body []byte, v interface{}) error {
+	return xml.Unmarshal(body, v)
+}
+
+// CSVRenderer renders text/csv with the rules of renderCSV.
+type CSVRenderer struct{}
+
+func (CSVRenderer) Render(w io.Writer, data interface{}) error {
+	return renderCSV(w, data)
+}
+
+// renderCSV writes [][]string as is, and a struct, a map with string keys or
+// a slice of either as a header line followed by a line per element.
+// Struct columns are named by their csv or json tag.
+func renderCSV(w io.Writer, data interface{}) error {
+	writer := csv.NewWriter(w)
+	if rows, ok := data.([][]string); ok {
+		return writer.WriteAll(rows)
+	}
+
+	v := indirect(reflect.ValueOf(data))
+	var items []reflect.Value
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		for i := 0; i < v.Len(); i++ {
+			items = append(items, indirect(v.Index(i)))
+		}
+	} else {
+		items = []reflect.Value{v}
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	var header []string
+	var row func(reflect.Value) []string
+	switch first := items[0]; {
+	case first.Kind() == reflect.Struct:
+		header = csvHeader(first.Type())
+		row = func(item reflect.Value) []string {
+			return csvRow(item, nil)
+		}
+	case first.Kind() == reflect.Map && first.Type().Key().Kind() == reflect.String:
+		for _, key := range first.MapKeys() {
+			header = append(header, key.String())
+		}
+		sort.Strings(header)
+		row = func(item reflect.Value) []string {
+			values := make([]string, len(header))
+			for i, key := range header {
+				values[i] = csvValue(item.MapIndex(reflect.ValueOf(key).Convert(item.Type().Key())))
+			}
+			return values
+		}
+	default:
+		return fmt.Errorf("csv: cannot render %T", data)
+	}
+
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, item := range items {
+		if item.Kind() != items[0].Kind() || item.Type() != items[0].Type() {
+			return fmt.Errorf("csv: cannot render %T", data)
+		}
+		if err := writer.Write(row(item)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func csvHeader(t reflect.Type) []string {
+	var header []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			header = append(header, csvHeader(field.Type)...)
+			continue
+		}
+		if name, ok := csvColumn(field); ok {
+			header = append(header, name)
+		}
+	}
+	return header
+}
+
+func csvRow(v reflect.Value, values []string) []string {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			values = csvRow(v.Field(i), values)
+			continue
+		}
+		if _, ok := csvColumn(field); ok {
+			values = append(values, csvValue(v.Field(i)))
+		}
+	}
+	return values
+}
+
+func csvColumn(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+	for _, tag := range []string{"csv", "json"} {
+		name := strings.Split(field.Tag.Get(tag), ",")[0]
+		if name == "-" {
+			return "", false
+		}
+		if name != "" {
+			return name, true
+		}
+	}
+	return field.Name, true
+}
+
+func csvValue(v reflect.Value) string {
+	if !v.IsValid() || isNilValue(v) {
+		return ""
+	}
+	if marshaler, ok := v.Interface().(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		if err == nil {
+			return string(text)
+		}
+	}
+	return fmt.Sprint(indirect(v).Interface())
+}
+
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return v
+		}
+		v = v.Elem()
+	}
+	return v
+}
@@ -0,0 +1,206 @@
+package forge
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type exportRow struct {
+	ID      int        `json:"id"`
+	Name    string     `csv:"full_name" json:"name"`
+	Secret  string     `json:"-"`
+	Created time.Time  `json:"created"`
+	Deleted *time.Time `json:"deleted"`
+}
+
+// kvRenderer renders and decodes map[string]string as key=value lines.
+type kvRenderer struct{}
+
+func (kvRenderer) Render(w io.Writer, data interface{}) error {
+	for key, value := range data.(map[string]string) {
+		if _, err := io.WriteString(w, key+"="+value+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (kvRenderer) Decode(body []byte, v interface{}) error {
+	values := v.(*map[string]string)
+	*values = make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(string(body)), "\n") {
+		key, value, _ := strings.Cut(line, "=")
+		(*values)[key] = value
+	}
+	return nil
+}
+
+func renderRequest(t *testing.T, app *Application, method, path, accept, contentType, body string) (*http.Response, string) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(data)
+}
+
+// registerXMLAndCSV opts app into the XML and CSV renderers.
+func registerXMLAndCSV(app *Application) {
+	app.RegisterRenderer("application/xml", XMLRenderer{})
+	app.RegisterRenderer("text/csv", CSVRenderer{})
+}
+
+func TestRenderNegotiatesMediaType(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	registerXMLAndCSV(app)
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	app.Group("/").Get("/export", func(ctx *Context) error {
+		return ctx.Render(http.StatusOK, []exportRow{{ID: 1, Name: "Ada", Secret: "x", Created: created}})
+	})
+
+	resp, body := renderRequest(t, app, http.MethodGet, "/export", "", "", "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"), "JSON is preferred when anything is accepted")
+	assert.JSONEq(t, `[{"id":1,"name":"Ada","created":"2024-05-01T12:00:00Z","deleted":null}]`, body)
+	assert.Contains(t, resp.Header.Get("Vary"), "Accept")
+
+	resp, body = renderRequest(t, app, http.MethodGet, "/export", "application/json;q=0.5, text/csv", "", "")
+	assert.Equal(t, "text/csv; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "id,full_name,created,deleted\n1,Ada,2024-05-01T12:00:00Z,\n", body)
+
+	resp, body = renderRequest(t, app, http.MethodGet, "/export", "application/xml", "", "")
+	assert.Equal(t, "application/xml; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Contains(t, body, "<exportRow><ID>1</ID><Name>Ada</Name>")
+
+	resp, body = renderRequest(t, app, http.MethodGet, "/export", "application/msgpack", "", "")
+	assert.Equal(t, http.StatusNotAcceptable, resp.StatusCode)
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(body), &decoded))
+	assert.Equal(t, map[string]interface{}{"supported": []interface{}{"application/json", "application/xml", "text/csv"}}, decoded["details"])
+	assert.Empty(t, ErrNotAcceptable.Details, "the shared error is left alone")
+}
+
+func TestRenderDefaultsToJSON(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	app.Group("/").Get("/data", func(ctx *Context) error {
+		return ctx.Render(http.StatusOK, H{"name": "ada"})
+	})
+
+	browser := "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+	resp, body := renderRequest(t, app, http.MethodGet, "/data", browser, "", "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	assert.JSONEq(t, `{"name":"ada"}`, body)
+
+	resp, _ = renderRequest(t, app, http.MethodGet, "/data", "text/csv", "", "")
+	assert.Equal(t, http.StatusNotAcceptable, resp.StatusCode, "CSV is opt-in")
+}
+
+func TestWithDetailCopiesDetails(t *testing.T) {
+	base := NewAppError("failed", http.StatusTeapot).WithDetail("a", 1)
+	derived := base.WithDetail("b", 2)
+	assert.Equal(t, map[string]interface{}{"a": 1}, base.Details)
+	assert.Equal(t, map[string]interface{}{"a": 1, "b": 2}, derived.Details)
+}
+
+func TestRegisterRenderer(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	app.RegisterRenderer("text/x-kv", kvRenderer{})
+	app.Group("/").Post("/echo", func(ctx *Context) error {
+		var values map[string]string
+		if err := ctx.Bind(&values); err != nil {
+			return err
+		}
+		values["seen"] = "yes"
+		return ctx.Render(http.StatusCreated, values)
+	})
+
+	resp, body := renderRequest(t, app, http.MethodPost, "/echo", "text/x-kv", "text/x-kv; charset=utf-8", "name=ada\n")
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "text/x-kv; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.ElementsMatch(t, []string{"name=ada", "seen=yes"}, strings.Fields(body))
+
+	resp, body = renderRequest(t, app, http.MethodPost, "/echo", "", "application/json", `{"name":"ada"}`)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.JSONEq(t, `{"name":"ada","seen":"yes"}`, body)
+}
+
+func TestTypedHandlerNegotiatesMediaType(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	registerXMLAndCSV(app)
+	require.NoError(t, app.RegisterController(&OrderController{}))
+
+	body := `<CreateOrderRequest><Item>book</Item><Quantity>2</Quantity><Tenant>acme</Tenant></CreateOrderRequest>`
+	resp, response := renderRequest(t, app, http.MethodPost, "/order", "application/xml", "application/xml", body)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "header fields are not read from the body")
+	assert.Contains(t, response, `"X-Tenant":"is required"`)
+
+	req := httptest.NewRequest(http.MethodPost, "/order", strings.NewReader(body))
+	req.Header.Set("Accept", "application/xml")
+	req.Header.Set("Content-Type", "application/xml")
+	req.Header.Set("X-Tenant", "acme")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	assert.Contains(t, string(data), "<OrderResponse><ID>1</ID><Item>book</Item><Quantity>2</Quantity><Tenant>acme</Tenant>")
+
+	spec, err := app.GenerateOpenAPI()
+	require.NoError(t, err)
+	create := spec.Paths["/order"].Post
+	require.NotNil(t, create)
+	assert.Equal(t, []string{"application/json", "application/xml"}, mediaTypes(create.RequestBody.Content))
+	assert.Equal(t, []string{"application/json", "application/xml", "text/csv"}, mediaTypes(create.Responses["201"].Content))
+	assert.Contains(t, create.Responses, "406")
+}
+
+func TestRenderCSV(t *testing.T) {
+	deleted := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		data interface{}
+		want string
+	}{
+		{[][]string{{"a", "b"}, {"1", "2,3"}}, "a,b\n1,\"2,3\"\n"},
+		{&exportRow{ID: 2, Deleted: &deleted}, "id,full_name,created,deleted\n2,,0001-01-01T00:00:00Z,2024-01-02T00:00:00Z\n"},
+		{[]map[string]int{{"b": 2, "a": 1}, {"a": 3}}, "a,b\n1,2\n3,\n"},
+		{struct {
+			exportRow
+			Extra bool
+		}{Extra: true}, "id,full_name,created,deleted,Extra\n0,,0001-01-01T00:00:00Z,,true\n"},
+		{[]exportRow{}, ""},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		require.NoError(t, renderCSV(&buf, tt.data))
+		assert.Equal(t, tt.want, buf.String())
+	}
+
+	assert.ErrorContains(t, renderCSV(io.Discard, 42), "csv: cannot render int")
+}
+
+func mediaTypes(content map[string]MediaTypeObject) []string {
+	var types []string
+	for mediaType := range content {
+		types = append(types, mediaType)
+	}
+	sort.Strings(types)
+	return types
+}
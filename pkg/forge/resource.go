@@ -0,0 +1,149 @@
+package forge
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// resourceActions lists the methods of a resource controller and the routes
+// they serve, relative to the resource path.
+var resourceActions = []struct {
+	name   string
+	method string
+	path   string
+}{
+	{"Index", http.MethodGet, ""},
+	{"Create", http.MethodPost, ""},
+	{"Show", http.MethodGet, "/:id"},
+	{"Update", http.MethodPut, "/:id"},
+	{"Update", http.MethodPatch, "/:id"},
+	{"Destroy", http.MethodDelete, "/:id"},
+}
+
+// ResourceOption restricts the actions served by Resource.
+type ResourceOption func(*resource)
+
+// Only serves just the named actions, e.g. Only("index", "show").
+func Only(actions ...string) ResourceOption {
+	return func(r *resource) {
+		r.only = append(r.only, actions...)
+	}
+}
+
+// Except serves every action but the named ones, e.g. Except("destroy").
+func Except(actions ...string) ResourceOption {
+	return func(r *resource) {
+		r.except = append(r.except, actions...)
+	}
+}
+
+// resource records the path and actions of a resource controller.
+type resource struct {
+	path   string
+	only   []string
+	except []string
+}
+
+// Resource mounts a controller's Index, Show, Create, Update and Destroy
+// methods as the REST routes of path:
+//
+//	GET    /posts      Index
+//	POST   /posts      Create
+//	GET    /posts/:id  Show
+//	PUT    /posts/:id  Update (PATCH too)
+//	DELETE /posts/:id  Destroy
+//
+// Path may contain parameters of parent resources, as in
+// "/users/:user_id/posts". Actions take the same signatures as Handle*
+// methods, which are routed by convention below path.
+func (app *Application) Resource(path string, controller interface{}, opts ...ResourceOption) error {
+	entry := controllerEntry{version: controllerVersion(controller)}
+	return app.registerResource(path, controller, entry, opts)
+}
+
+// Resource mounts a resource controller below the router's prefix.
+func (r *Router) Resource(path string, controller interface{}, opts ...ResourceOption) error {
+	entry := controllerEntry{
+		prefix:     r.prefix,
+		version:    controllerVersion(controller),
+		middleware: append([]MiddlewareFunc(nil), r.middleware...),
+	}
+	return r.app.registerResource(path, controller, entry, opts)
+}
+
+// Resource mounts a resource controller under this version.
+func (v *APIVersion) Resource(path string, controller interface{}, opts ...ResourceOption) error {
+	return v.app.registerResource(path, controller, controllerEntry{version: v.name}, opts)
+}
+
+func (app *Application) registerResource(path string, controller interface{}, entry controllerEntry, opts []ResourceOption) error {
+	entry.resource = &resource{path: joinPath(path)}
+	for _, opt := range opts {
+		opt(entry.resource)
+	}
+	return app.registerController(controller, entry)
+}
+
+// routes returns the routes of the resource actions controllerType
+// implements, below prefix.
+func (r *resource) routes(controllerType reflect.Type, prefix string) ([]controllerRoute, error) {
+	controllerName := controllerType.Elem().Name()
+	only, err := resourceActionSet(r.only)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", controllerName, err)
+	}
+	except, err := resourceActionSet(r.except)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", controllerName, err)
+	}
+
+	var routes []controllerRoute
+	implemented := false
+	for _, action := range resourceActions {
+		method, ok := controllerType.MethodByName(action.name)
+		if ok {
+			implemented = true
+		}
+		if (len(only) > 0 && !only[action.name]) || except[action.name] {
+			continue
+		}
+		if !ok {
+			if only[action.name] {
+				return nil, fmt.Errorf("%s has no %s method", controllerName, action.name)
+			}
+			continue
+		}
+
+		if _, err := parseHandlerSignature(method); err != nil {
+			return nil, fmt.Errorf("%s.%w", controllerName, err)
+		}
+		info := RouteInfo{HTTPMethod: action.method, Path: joinPath(prefix, r.path, action.path)}
+		routes = append(routes, controllerRoute{RouteInfo: info, method: method})
+	}
+
+	if !implemented {
+		return nil, fmt.Errorf("%s has none of the Index, Show, Create, Update and Destroy methods", controllerName)
+	}
+	return routes, nil
+}
+
+// resourceActionSet normalizes action names such as "index" to the method
+// names they refer to.
+func resourceActionSet(actions []string) (map[string]bool, error) {
+	set := make(map[string]bool)
+	for _, action := range actions {
+		known := false
+		for _, a := range resourceActions {
+			if strings.EqualFold(a.name, action) {
+				set[a.name] = true
+				known = true
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown resource action %q", action)
+		}
+	}
+	return set, nil
+}
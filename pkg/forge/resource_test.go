@@ -0,0 +1,163 @@
+package forge
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ArticleController struct {
+	Controller
+}
+
+type UpdateArticleRequest struct {
+	ID    int    `path:"id"`
+	Title string `json:"title" validate:"required"`
+}
+
+func (c *ArticleController) Index(ctx *Context) error {
+	return ctx.SendString("index " + ctx.Param("user_id"))
+}
+
+func (c *ArticleController) Show(ctx *Context) error {
+	return ctx.SendString("show " + ctx.Param("user_id") + "/" + ctx.Param("id"))
+}
+
+func (c *ArticleController) Create(ctx *Context) error {
+	return ctx.Status(http.StatusCreated).SendString("create")
+}
+
+func (c *ArticleController) Update(ctx *Context, req *UpdateArticleRequest) (*UpdateArticleRequest, error) {
+	return req, nil
+}
+
+func (c *ArticleController) Destroy(ctx *Context) error {
+	return ctx.SendStatus(http.StatusNoContent)
+}
+
+func (c *ArticleController) HandlePostPublishById(ctx *Context) error {
+	return ctx.SendString("publish " + ctx.Param("id"))
+}
+
+type GuardedArticleController struct {
+	ArticleController
+}
+
+func (c *GuardedArticleController) Middleware() map[string][]MiddlewareFunc {
+	guard := []MiddlewareFunc{func(next HandlerFunc) HandlerFunc {
+		return func(ctx *Context) error { return ErrUnauthorized }
+	}}
+	return map[string][]MiddlewareFunc{"Create": guard, "Update": guard}
+}
+
+func (c *GuardedArticleController) Routes() []RouteMetadata {
+	return []RouteMetadata{{Handler: "Destroy", Path: "/articles/:id/remove", Method: http.MethodPost}}
+}
+
+type PlainController struct {
+	Controller
+}
+
+func (c *PlainController) HandleGetIndex(ctx *Context) error { return nil }
+
+func sendResource(t *testing.T, app *Application, method, path, body string) (int, string) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(data)
+}
+
+func TestResourceRoutes(t *testing.T) {
+	app := newTestApp(t, &Config{Server: ServerConfig{BasePath: "/api"}})
+	require.NoError(t, app.Resource("/users/:user_id/articles", &ArticleController{}))
+
+	tests := []struct {
+		method, path, body string
+		status             int
+		response           string
+	}{
+		{http.MethodGet, "/api/users/3/articles", "", http.StatusOK, "index 3"},
+		{http.MethodPost, "/api/users/3/articles", "", http.StatusCreated, "create"},
+		{http.MethodGet, "/api/users/3/articles/9", "", http.StatusOK, "show 3/9"},
+		{http.MethodPut, "/api/users/3/articles/9", `{"title":"new"}`, http.StatusOK, `{"ID":9,"title":"new"}`},
+		{http.MethodPatch, "/api/users/3/articles/9", `{"title":"new"}`, http.StatusOK, `{"ID":9,"title":"new"}`},
+		{http.MethodDelete, "/api/users/3/articles/9", "", http.StatusNoContent, ""},
+		{http.MethodPost, "/api/users/3/articles/publish/9", "", http.StatusOK, "publish 9"},
+	}
+	for _, tt := range tests {
+		status, body := sendResource(t, app, tt.method, tt.path, tt.body)
+		assert.Equal(t, tt.status, status, tt.method+" "+tt.path)
+		assert.Equal(t, tt.response, body, tt.method+" "+tt.path)
+	}
+
+	route := findRoute(app.Routes(), http.MethodGet, "/api/users/:user_id/articles/:id")
+	require.NotNil(t, route)
+	assert.Equal(t, "Show", route.Handler)
+	assert.Equal(t, "forge.ArticleController", route.Controller)
+
+	spec, err := app.GenerateOpenAPI()
+	require.NoError(t, err)
+	item := spec.Paths["/api/users/{user_id}/articles/{id}"]
+	require.NotNil(t, item.Put)
+	require.NotNil(t, item.Patch)
+	assert.NotEqual(t, item.Put.OperationID, item.Patch.OperationID)
+}
+
+func TestResourceOnlyExcept(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	require.NoError(t, app.Resource("/articles", &ArticleController{}, Only("index", "Show")))
+	assert.NotNil(t, findRoute(app.Routes(), http.MethodGet, "/articles"))
+	assert.NotNil(t, findRoute(app.Routes(), http.MethodGet, "/articles/:id"))
+	assert.Nil(t, findRoute(app.Routes(), http.MethodPost, "/articles"))
+
+	app = newTestApp(t, &Config{})
+	require.NoError(t, app.Group("/v2").Resource("/articles", &ArticleController{}, Except("destroy", "update")))
+	assert.NotNil(t, findRoute(app.Routes(), http.MethodPost, "/v2/articles"))
+	assert.Nil(t, findRoute(app.Routes(), http.MethodDelete, "/v2/articles/:id"))
+	assert.Nil(t, findRoute(app.Routes(), http.MethodPatch, "/v2/articles/:id"))
+}
+
+func TestResourceActionMiddlewareAndRoutes(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	require.NoError(t, app.Resource("/articles", &GuardedArticleController{}))
+
+	tests := []struct {
+		method, path string
+		status       int
+	}{
+		{http.MethodGet, "/articles", http.StatusOK},
+		{http.MethodPost, "/articles", http.StatusUnauthorized},
+		{http.MethodPut, "/articles/1", http.StatusUnauthorized},
+		{http.MethodPatch, "/articles/1", http.StatusUnauthorized},
+		{http.MethodPost, "/articles/1/remove", http.StatusNoContent},
+		{http.MethodDelete, "/articles/1", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		status, _ := sendResource(t, app, tt.method, tt.path, `{"title":"new"}`)
+		assert.Equal(t, tt.status, status, tt.method+" "+tt.path)
+	}
+
+	err := newTestApp(t, &Config{}).RegisterController(&GuardedArticleController{})
+	assert.ErrorContains(t, err, `GuardedArticleController.Routes: unknown handler "Destroy"`, "actions are only handlers of resources")
+}
+
+func TestResourceErrors(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	err := app.Resource("/articles", &ArticleController{}, Only("list"))
+	assert.ErrorContains(t, err, `ArticleController: unknown resource action "list"`)
+
+	err = app.Resource("/plain", &PlainController{})
+	assert.ErrorContains(t, err, "PlainController has none of the Index, Show, Create, Update and Destroy methods")
+
+	err = app.Resource("/plain", &PlainController{}, Only("show"))
+	assert.ErrorContains(t, err, "PlainController has no Show method")
+}
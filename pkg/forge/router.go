@@ -0,0 +1,158 @@
+package forge
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// contextKey is the Fiber local holding the request's *Context.
+const contextKey = "forge.context"
+
+// Router mounts handlers and controllers under a path prefix, wrapping them
+// in the router's middleware. Routers are created with app.Group.
+type Router struct {
+	app        *Application
+	prefix     string
+	middleware []MiddlewareFunc
+}
+
+// Group returns a router for the routes under prefix, itself below
+// Server.BasePath.
+func (app *Application) Group(prefix string) *Router {
+	return &Router{app: app, prefix: joinPath(prefix)}
+}
+
+// Use adds middleware running before every route of the application,
+// including routes registered earlier.
+func (app *Application) Use(middleware ...MiddlewareFunc) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+	app.middleware = append(app.middleware, middleware...)
+}
+
+// handleMiddleware runs the middleware added with Use, then the rest of
+// the Fiber stack.
+func (app *Application) handleMiddleware(c *fiber.Ctx) error {
+	app.mu.RLock()
+	middleware := app.middleware
+	app.mu.RUnlock()
+	if len(middleware) == 0 {
+		return c.Next()
+	}
+
+	handler := func(*Context) error {
+		return c.Next()
+	}
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler(app.context(c))
+}
+
+// context returns the Context of the request, creating it on first use so
+// middleware and handlers share it.
+func (app *Application) context(c *fiber.Ctx) *Context {
+	if ctx, ok := c.Locals(contextKey).(*Context); ok {
+		return ctx
+	}
+	ctx := NewContext(c, app)
+	c.Locals(contextKey, ctx)
+	return ctx
+}
+
+// Prefix returns the path the router's routes are mounted under.
+func (r *Router) Prefix() string {
+	return joinPath(r.app.config.Server.BasePath, r.prefix)
+}
+
+// Group returns a router for the routes under prefix, relative to this
+// router. It starts with this router's middleware.
+func (r *Router) Group(prefix string) *Router {
+	return &Router{
+		app:        r.app,
+		prefix:     joinPath(r.prefix, prefix),
+		middleware: append([]MiddlewareFunc(nil), r.middleware...),
+	}
+}
+
+// Use adds middleware wrapping the handlers and controllers registered on
+// the router afterwards.
+func (r *Router) Use(middleware ...MiddlewareFunc) *Router {
+	r.middleware = append(r.middleware, middleware...)
+	return r
+}
+
+func (r *Router) Get(path string, handler HandlerFunc) *Router {
+	return r.Add(http.MethodGet, path, handler)
+}
+
+func (r *Router) Post(path string, handler HandlerFunc) *Router {
+	return r.Add(http.MethodPost, path, handler)
+}
+
+func (r *Router) Put(path string, handler HandlerFunc) *Router {
+	return r.Add(http.MethodPut, path, handler)
+}
+
+func (r *Router) Patch(path string, handler HandlerFunc) *Router {
+	return r.Add(http.MethodPatch, path, handler)
+}
+
+func (r *Router) Delete(path string, handler HandlerFunc) *Router {
+	return r.Add(http.MethodDelete, path, handler)
+}
+
+// Add serves handler for method and path below the router's prefix. It
+// panics if a controller or another handler already serves the route.
+func (r *Router) Add(method, path string, handler HandlerFunc) *Router {
+	app := r.app
+	fullPath := joinPath(r.Prefix(), path)
+
+	wrapped := handler
+	for i := len(r.middleware) - 1; i >= 0; i-- {
+		wrapped = r.middleware[i](wrapped)
+	}
+
+	var middleware []string
+	for _, m := range r.middleware {
+		middleware = append(middleware, funcName(m))
+	}
+	app.mu.Lock()
+	if app.routeOwners == nil {
+		app.routeOwners = make(map[string]string)
+	}
+	key := method + " " + routePattern(fullPath, app.server.Config().CaseSensitive)
+	if existing, ok := app.routeOwners[key]; ok {
+		app.mu.Unlock()
+		panic(fmt.Sprintf("forge: route %s %s of %s conflicts with %s", method, fullPath, funcName(handler), existing))
+	}
+	app.routeOwners[key] = funcName(handler)
+	if app.handlerRoutes == nil {
+		app.handlerRoutes = make(map[string]Route)
+	}
+	app.handlerRoutes[method+" "+fullPath] = Route{Handler: funcName(handler), Middleware: middleware}
+	app.mu.Unlock()
+
+	fiberHandler := func(c *fiber.Ctx) error {
+		return wrapped(app.context(c))
+	}
+	if method == http.MethodGet {
+		app.server.Get(fullPath, fiberHandler)
+	} else {
+		app.server.Add(method, fullPath, fiberHandler)
+	}
+	return r
+}
+
+// RegisterController mounts controller below the router's prefix, wrapping
+// its handlers in the router's middleware before its own.
+func (r *Router) RegisterController(controller interface{}) error {
+	entry := controllerEntry{
+		prefix:     r.prefix,
+		version:    controllerVersion(controller),
+		middleware: append([]MiddlewareFunc(nil), r.middleware...),
+	}
+	return r.app.registerController(controller, entry)
+}
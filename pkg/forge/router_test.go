@@ -0,0 +1,95 @@
+package forge
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func traceHandler(ctx *Context) error {
+	return ctx.SendString(string(ctx.Response().Header.Peek("X-Trace")))
+}
+
+func TestRouterMiddleware(t *testing.T) {
+	app := newTestApp(t, &Config{Server: ServerConfig{BasePath: "/api"}})
+
+	api := app.Group("/v2").Use(traceMiddleware("group"))
+	api.Get("/ping", traceHandler)
+	admin := api.Group("/admin").Use(traceMiddleware("admin"))
+	require.NoError(t, admin.RegisterController(&AccountController{}))
+	app.Use(traceMiddleware("global"))
+
+	status, body := getBody(t, app, "/api/v2/ping")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "global,group", body, "app.Use applies to routes registered before it")
+
+	status, body = getBody(t, app, "/api/v2/admin/account/settings")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "global,group,admin,method", body)
+	assert.Equal(t, "/api/v2/admin", admin.Prefix())
+
+	status, _ = getBody(t, app, "/api/v2/admin/ping")
+	assert.Equal(t, http.StatusNotFound, status, "routes are only served by the router they were added to")
+}
+
+func TestRouterSharesContext(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	app.Use(func(next HandlerFunc) HandlerFunc {
+		return func(ctx *Context) error {
+			ctx.Locals("first", ctx)
+			return next(ctx)
+		}
+	})
+	app.Group("/").Use(func(next HandlerFunc) HandlerFunc {
+		return func(ctx *Context) error {
+			assert.Same(t, ctx.Locals("first"), ctx)
+			assert.Same(t, app, ctx.App())
+			return next(ctx)
+		}
+	}).Post("/same", func(ctx *Context) error {
+		if ctx.Locals("first") != ctx {
+			return ctx.SendString("different")
+		}
+		return ctx.SendString("same")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/same", nil))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "same", string(body))
+}
+
+func TestRoutesListRouterRoutes(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	app.Use(traceMiddleware("global"))
+	app.Group("/tools").Use(traceMiddleware("group")).Get("/trace", traceHandler)
+
+	route := findRoute(app.Routes(), http.MethodGet, "/tools/trace")
+	require.NotNil(t, route)
+	assert.Equal(t, "forge.traceHandler", route.Handler)
+	assert.Equal(t, []string{"recover.New", "logger.New", "cors.New", "forge.traceMiddleware", "forge.traceMiddleware"}, route.Middleware)
+}
+
+func TestRouterRejectsDuplicateRoutes(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	app.Group("/post").Get("/:slug", traceHandler)
+	err := app.RegisterController(&PostController{})
+	assert.ErrorContains(t, err, "route GET /post/:id of PostController.HandleGetPostById conflicts with forge.traceHandler")
+
+	app = newTestApp(t, &Config{})
+	require.NoError(t, app.RegisterController(&PostController{}))
+	assert.PanicsWithValue(t, "forge: route GET /post/:slug of forge.traceHandler conflicts with PostController.HandleGetPostById", func() {
+		app.Group("/post").Get("/:slug", traceHandler)
+	})
+	assert.Panics(t, func() {
+		app.Group("/").Get("/post/:other", traceHandler)
+	}, "parameter names don't matter")
+	assert.NotPanics(t, func() {
+		app.Group("/post").Post("/:slug", traceHandler)
+	})
+}
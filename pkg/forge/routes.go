@@ -0,0 +1,234 @@
+package forge
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RoutesFileEnv names a file Start writes the route table to as JSON instead
+// of listening. The forge routes command uses it to inspect a project: it
+// only applies to the empty file the command creates, and never in
+// production.
+const RoutesFileEnv = "FORGE_ROUTES_FILE"
+
+// Route describes a route registered with the application.
+type Route struct {
+	Method     string   `json:"method"`
+	Path       string   `json:"path"`
+	Controller string   `json:"controller,omitempty"`
+	Module     string   `json:"module,omitempty"`
+	Handler    string   `json:"handler"`
+	Middleware []string `json:"middleware,omitempty"`
+	Version    string   `json:"version,omitempty"`
+}
+
+// Routes returns every route served by the application, sorted by path and
+// method. Controller routes carry the controller type, Go method name and API
+// version, and routes added through a Router the name of their handler.
+// The middleware chain lists the middleware that runs before the handler,
+// in order.
+func (app *Application) Routes() []Route {
+	controllerRoutes := make(map[string]Route)
+	app.mu.RLock()
+	for _, entry := range app.controllers {
+		controllerName := reflect.TypeOf(entry.controller).String()
+		controllerName = strings.TrimPrefix(controllerName, "*")
+		var middleware []string
+		for _, m := range entry.middleware {
+			middleware = append(middleware, funcName(m))
+		}
+		for _, route := range entry.routes {
+			controllerRoutes[route.HTTPMethod+" "+route.Path] = Route{
+				Controller: controllerName,
+				Module:     entry.module,
+				Handler:    route.method.Name,
+				Middleware: middleware,
+				Version:    entry.version,
+			}
+		}
+	}
+	for key, route := range app.handlerRoutes {
+		controllerRoutes[key] = route
+	}
+	var globalMiddleware []string
+	for _, m := range app.middleware {
+		globalMiddleware = append(globalMiddleware, funcName(m))
+	}
+	app.mu.RUnlock()
+	handleMiddleware := funcName(app.handleMiddleware)
+
+	// Stack also holds middleware registered with Use; GetRoutes(true) leaves
+	// those out, which is how the two are told apart.
+	handlers := make(map[string]bool)
+	for _, r := range app.server.GetRoutes(true) {
+		handlers[routeKey(r.Method, r.Path, r.Handlers)] = true
+	}
+
+	var routes []Route
+	for _, stack := range app.server.Stack() {
+		var middleware []*fiber.Route
+		for _, r := range stack {
+			if !handlers[routeKey(r.Method, r.Path, r.Handlers)] {
+				middleware = append(middleware, r)
+				continue
+			}
+			// Fiber adds a HEAD route for every GET; list only the GET.
+			if r.Method == fiber.MethodHead && handlers[routeKey(fiber.MethodGet, r.Path, r.Handlers)] {
+				continue
+			}
+
+			route, ok := controllerRoutes[r.Method+" "+r.Path]
+			if !ok {
+				route = Route{Handler: funcName(r.Handlers[len(r.Handlers)-1])}
+			}
+			route.Method = r.Method
+			route.Path = r.Path
+			handlerMiddleware := route.Middleware
+			route.Middleware = nil
+			for _, m := range middleware {
+				if !pathHasPrefix(r.Path, m.Path) {
+					continue
+				}
+				// Fiber merges consecutive Use calls on the same prefix.
+				for _, h := range m.Handlers {
+					// Middleware added with app.Use runs inside a single handler.
+					if name := funcName(h); name == handleMiddleware {
+						route.Middleware = append(route.Middleware, globalMiddleware...)
+					} else {
+						route.Middleware = append(route.Middleware, name)
+					}
+				}
+			}
+			route.Middleware = append(route.Middleware, handlerMiddleware...)
+			routes = append(routes, route)
+		}
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
+}
+
+// URL builds the path of a controller route from its name, the controller
+// type and method such as "UserController.HandleGetUserById" or
+// "PostController.Show", filling its parameters in order.
+func (app *Application) URL(name string, params ...interface{}) (string, error) {
+	controllerName, handler, ok := strings.Cut(name, ".")
+	if !ok {
+		return "", fmt.Errorf("route name %q must be Controller.Handler", name)
+	}
+
+	app.mu.RLock()
+	defer app.mu.RUnlock()
+	for _, entry := range app.controllers {
+		if reflect.TypeOf(entry.controller).Elem().Name() != controllerName {
+			continue
+		}
+		for _, route := range entry.routes {
+			if route.method.Name == handler {
+				return fillPath(name, route.Path, params)
+			}
+		}
+	}
+	return "", fmt.Errorf("no route named %q", name)
+}
+
+func fillPath(name, path string, params []interface{}) (string, error) {
+	segments := strings.Split(path, "/")
+	filled := 0
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, ":") {
+			continue
+		}
+		if filled == len(params) {
+			if strings.HasSuffix(segment, "?") {
+				segments[i] = ""
+				continue
+			}
+			return "", fmt.Errorf("route %q needs a value for %s", name, segment)
+		}
+		segments[i] = url.PathEscape(fmt.Sprint(params[filled]))
+		filled++
+	}
+	if filled < len(params) {
+		return "", fmt.Errorf("route %q takes %d parameters, got %d", name, filled, len(params))
+	}
+	return joinPath(segments...), nil
+}
+
+// routesFile returns the file the forge routes command asked the route
+// table to be written to, or "" to start normally. A leftover or inherited
+// RoutesFileEnv is ignored with a warning, so it never keeps a server from
+// starting.
+func (app *Application) routesFile() string {
+	path := os.Getenv(RoutesFileEnv)
+	if path == "" {
+		return ""
+	}
+	os.Unsetenv(RoutesFileEnv)
+
+	if app.IsProduction() {
+		app.logger.Warn("Ignoring %s in production", RoutesFileEnv)
+		return ""
+	}
+	if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() || info.Size() != 0 {
+		app.logger.Warn("Ignoring %s=%s: not an empty file created by forge routes", RoutesFileEnv, path)
+		return ""
+	}
+	return path
+}
+
+// writeRoutes dumps the route table as JSON to path.
+func (app *Application) writeRoutes(path string) error {
+	data, err := json.MarshalIndent(app.Routes(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func routeKey(method, path string, handlers []fiber.Handler) string {
+	return fmt.Sprintf("%s %s %x", method, path, reflect.ValueOf(handlers[0]).Pointer())
+}
+
+// pathHasPrefix reports whether middleware mounted at prefix applies to path.
+func pathHasPrefix(path, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return prefix == "" || path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// funcName returns a short, readable name for a handler such as
+// "recover.New" or "forge.(*Application).handleLiveness".
+func funcName(fn interface{}) string {
+	name := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.TrimSuffix(name, "-fm")
+
+	// Closures are named pkg.Outer.func1 or pkg.Outer.func1.2.
+	for {
+		i := strings.LastIndex(name, ".")
+		if i < 0 {
+			break
+		}
+		suffix := strings.TrimPrefix(name[i+1:], "func")
+		if suffix == "" || strings.Trim(suffix, "0123456789") != "" {
+			break
+		}
+		name = name[:i]
+	}
+	return name
+}
@@ -0,0 +1,85 @@
+package forge
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func findRoute(routes []Route, method, path string) *Route {
+	for i := range routes {
+		if routes[i].Method == method && routes[i].Path == path {
+			return &routes[i]
+		}
+	}
+	return nil
+}
+
+func TestRoutesDescribeControllers(t *testing.T) {
+	app := newTestApp(t, &Config{Server: ServerConfig{BasePath: "/api"}})
+	require.NoError(t, app.Version("v1").RegisterController(&WidgetController{}))
+	require.NoError(t, app.RegisterController(&WidgetV2Controller{}))
+
+	routes := app.Routes()
+
+	route := findRoute(routes, "GET", "/api/v1/widget/widgets")
+	require.NotNil(t, route)
+	assert.Equal(t, "forge.WidgetController", route.Controller)
+	assert.Equal(t, "HandleGetWidgets", route.Handler)
+	assert.Equal(t, "v1", route.Version)
+	assert.Equal(t, []string{"recover.New", "logger.New", "cors.New"}, route.Middleware)
+
+	route = findRoute(routes, "GET", "/api/v2/widgetv2/widgets")
+	require.NotNil(t, route)
+	assert.Equal(t, "v2", route.Version)
+
+	route = findRoute(routes, "GET", "/healthz")
+	require.NotNil(t, route)
+	assert.Empty(t, route.Controller)
+	assert.Equal(t, "forge.(*Application).handleLiveness", route.Handler)
+
+	assert.Nil(t, findRoute(routes, "HEAD", "/healthz"), "implicit HEAD routes are not listed")
+}
+
+func TestStartWritesRoutesFile(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	require.NoError(t, app.RegisterController(&WidgetController{}))
+
+	path := filepath.Join(t.TempDir(), "routes.json")
+	require.NoError(t, os.WriteFile(path, nil, 0644))
+	t.Setenv(RoutesFileEnv, path)
+	require.NoError(t, app.Start())
+	assert.Empty(t, os.Getenv(RoutesFileEnv), "not passed on to child processes")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var routes []Route
+	require.NoError(t, json.Unmarshal(data, &routes))
+	assert.NotNil(t, findRoute(routes, "GET", "/widget/widgets"))
+}
+
+func TestRoutesFileIgnoredUnlessRequested(t *testing.T) {
+	dir := t.TempDir()
+	written := filepath.Join(dir, "written.json")
+	require.NoError(t, os.WriteFile(written, []byte("[]"), 0644))
+	empty := filepath.Join(dir, "empty.json")
+	require.NoError(t, os.WriteFile(empty, nil, 0644))
+
+	app := newTestApp(t, &Config{})
+	for _, path := range []string{filepath.Join(dir, "missing.json"), written, dir} {
+		t.Setenv(RoutesFileEnv, path)
+		assert.Empty(t, app.routesFile(), path)
+	}
+
+	t.Setenv(RoutesFileEnv, empty)
+	assert.Equal(t, empty, app.routesFile())
+
+	production := newTestApp(t, &Config{Environment: EnvProduction})
+	t.Setenv(RoutesFileEnv, empty)
+	assert.Empty(t, production.routesFile())
+}
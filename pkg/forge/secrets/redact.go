@@ -0,0 +1,126 @@
+package secrets
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Mask replaces secret values in redacted output.
+const Mask = "******"
+
+// IsSecret reports whether a struct field is tagged `secret:"true"`.
+func IsSecret(field reflect.StructField) bool {
+	return field.Tag.Get("secret") == "true"
+}
+
+// secretKeys are the words that make an untyped map key look like a secret.
+var secretKeys = []string{"password", "passwd", "secret", "token", "apikey", "privatekey", "credential"}
+
+// IsSecretKey reports whether a map key such as "api_key" or "webhookSecret"
+// names a secret.
+func IsSecretKey(key string) bool {
+	key = strings.NewReplacer("_", "", "-", "", ".", "").Replace(strings.ToLower(key))
+	for _, word := range secretKeys {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact returns a deep copy of v in which every non-empty string field
+// tagged `secret:"true"` is replaced by Mask, as are the values of untyped
+// map entries whose key IsSecretKey, such as those of module config
+// sections. Structs, pointers, interfaces, maps and slices are followed; v
+// itself is never modified.
+func Redact(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	return redact(reflect.ValueOf(v)).Interface()
+}
+
+func redact(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type().Elem())
+		out.Elem().Set(redact(v.Elem()))
+		return out
+
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			if IsSecret(field) && field.Type.Kind() == reflect.String {
+				if v.Field(i).Len() > 0 {
+					out.Field(i).SetString(Mask)
+				}
+				continue
+			}
+			out.Field(i).Set(redact(v.Field(i)))
+		}
+		return out
+
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		maskable := v.Type().Elem().Kind() == reflect.Interface || v.Type().Elem().Kind() == reflect.String
+		iter := v.MapRange()
+		for iter.Next() {
+			value := iter.Value()
+			if maskable && isSecretMapKey(iter.Key()) && !isEmpty(value) {
+				out.SetMapIndex(iter.Key(), reflect.ValueOf(Mask).Convert(v.Type().Elem()))
+				continue
+			}
+			out.SetMapIndex(iter.Key(), redact(value))
+		}
+		return out
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(redact(v.Elem()))
+		return out
+
+	case reflect.Slice:
+		if v.IsNil() || v.Type().Elem().Kind() == reflect.Uint8 {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(redact(v.Index(i)))
+		}
+		return out
+
+	default:
+		return v
+	}
+}
+
+func isSecretMapKey(key reflect.Value) bool {
+	if key.Kind() == reflect.Interface && !key.IsNil() {
+		key = key.Elem()
+	}
+	return key.Kind() == reflect.String && IsSecretKey(key.String())
+}
+
+func isEmpty(v reflect.Value) bool {
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return true
+		}
+		v = v.Elem()
+	}
+	return v.IsZero()
+}
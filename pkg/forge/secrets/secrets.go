@@ -0,0 +1,171 @@
+// Package secrets resolves secret values such as passwords and signing keys
+// from the environment, dotenv-style files or mounted secret directories, and
+// masks fields tagged `secret:"true"` before values are logged or dumped.
+package secrets
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultMountDir is where Docker and Kubernetes mount secrets by default.
+const DefaultMountDir = "/run/secrets"
+
+// ErrNotFound is returned by providers that do not hold the requested secret.
+var ErrNotFound = errors.New("secret not found")
+
+// Provider looks up secrets by key.
+type Provider interface {
+	Get(ctx context.Context, key string) (string, error)
+}
+
+// ProviderFunc adapts a function to the Provider interface.
+type ProviderFunc func(ctx context.Context, key string) (string, error)
+
+func (f ProviderFunc) Get(ctx context.Context, key string) (string, error) {
+	return f(ctx, key)
+}
+
+// Default returns the provider used when none is configured: environment
+// variables first, then files in DefaultMountDir.
+func Default() Provider {
+	return Chain(Env(), Mount(DefaultMountDir))
+}
+
+// Env returns a provider backed by environment variables.
+func Env() Provider {
+	return ProviderFunc(func(ctx context.Context, key string) (string, error) {
+		value, ok := os.LookupEnv(key)
+		if !ok {
+			return "", ErrNotFound
+		}
+		return value, nil
+	})
+}
+
+// File returns a provider backed by a dotenv-style file of KEY=value lines.
+// Blank lines and lines starting with # are ignored and values may be quoted.
+// The file is read on every lookup so rotated secrets are picked up.
+func File(path string) Provider {
+	return ProviderFunc(func(ctx context.Context, key string) (string, error) {
+		f, err := os.Open(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to open secrets file: %w", err)
+		}
+		defer f.Close()
+
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			name, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+			if !ok || strings.TrimSpace(name) != key {
+				continue
+			}
+			return unquote(strings.TrimSpace(value)), nil
+		}
+		if err := scanner.Err(); err != nil {
+			return "", fmt.Errorf("failed to read secrets file: %w", err)
+		}
+		return "", ErrNotFound
+	})
+}
+
+// Mount returns a provider backed by a directory holding one file per
+// secret, as mounted by Docker secrets or Kubernetes secret volumes. The key
+// is used as the file name, falling back to its lower-case form so
+// DB_PASSWORD also finds /run/secrets/db_password. Trailing newlines are
+// trimmed.
+func Mount(dir string) Provider {
+	return ProviderFunc(func(ctx context.Context, key string) (string, error) {
+		if key == "" || strings.ContainsAny(key, `/\`) || key == "." || key == ".." {
+			return "", ErrNotFound
+		}
+		for _, name := range []string{key, strings.ToLower(key)} {
+			value, err := ReadFile(filepath.Join(dir, name))
+			if err == nil {
+				return value, nil
+			}
+			if !errors.Is(err, os.ErrNotExist) {
+				return "", err
+			}
+		}
+		return "", ErrNotFound
+	})
+}
+
+// Chain returns a provider that asks each provider in turn and returns the
+// first secret found.
+func Chain(providers ...Provider) Provider {
+	return ProviderFunc(func(ctx context.Context, key string) (string, error) {
+		for _, p := range providers {
+			value, err := p.Get(ctx, key)
+			if errors.Is(err, ErrNotFound) {
+				continue
+			}
+			return value, err
+		}
+		return "", ErrNotFound
+	})
+}
+
+// ReadFile reads a secret stored in a file, trimming trailing newlines.
+func ReadFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// Expand replaces ${KEY} references in s with secrets from p. A reference
+// may carry a fallback, ${KEY:-default}, used when the secret does not
+// exist; without one a missing secret is an error. $${ produces a literal ${.
+func Expand(ctx context.Context, s string, p Provider) (string, error) {
+	var b strings.Builder
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+		if i > 0 && s[i-1] == '$' {
+			b.WriteString(s[:i])
+			b.WriteString("{")
+			s = s[i+2:]
+			continue
+		}
+		end := strings.Index(s[i:], "}")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated secret reference in %q", s)
+		}
+
+		b.WriteString(s[:i])
+		key, fallback, hasFallback := strings.Cut(s[i+2:i+end], ":-")
+		value, err := p.Get(ctx, key)
+		switch {
+		case errors.Is(err, ErrNotFound) && hasFallback:
+			value = fallback
+		case errors.Is(err, ErrNotFound):
+			return "", fmt.Errorf("secret %s is not set", key)
+		case err != nil:
+			return "", fmt.Errorf("failed to resolve secret %s: %w", key, err)
+		}
+		b.WriteString(value)
+		s = s[i+end+1:]
+	}
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
@@ -0,0 +1,390 @@
+package forge
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BisiOlaYemi/forge/pkg/forge/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Server-Sent Events defaults, used when the matching SSEConfig field is
+// zero.
+const (
+	DefaultSSEHeartbeat    = 15 * time.Second
+	DefaultSSEReplayBuffer = 100
+	DefaultSSESendBuffer   = 64
+)
+
+// ErrStreamBehind ends Listen when a stream doesn't keep up with the
+// published events. The client reconnects and resumes from the replay
+// buffer.
+var ErrStreamBehind = errors.New("event stream fell behind")
+
+// SSEConfig configures the event streams opened with Context.SSE.
+type SSEConfig struct {
+	Heartbeat    time.Duration `yaml:"heartbeat"`     // idle time before a keep-alive comment, defaults to 15s
+	Retry        time.Duration `yaml:"retry"`         // reconnection delay suggested to clients, unset by default
+	ReplayBuffer int           `yaml:"replay_buffer"` // published events kept per topic for resuming, defaults to 100
+	SendBuffer   int           `yaml:"send_buffer"`   // events queued per stream, defaults to 64
+}
+
+func (c SSEConfig) withDefaults() SSEConfig {
+	if c.Heartbeat == 0 {
+		c.Heartbeat = DefaultSSEHeartbeat
+	}
+	if c.ReplayBuffer == 0 {
+		c.ReplayBuffer = DefaultSSEReplayBuffer
+	}
+	if c.SendBuffer == 0 {
+		c.SendBuffer = DefaultSSESendBuffer
+	}
+	return c
+}
+
+// Event is a Server-Sent Event. Data is sent as is if it is a string or
+// []byte, and as JSON otherwise.
+type Event struct {
+	ID    string
+	Event string // "message" in the browser if empty
+	Data  interface{}
+	Retry time.Duration
+}
+
+// encode formats the event for the wire.
+func (e Event) encode() ([]byte, error) {
+	if strings.ContainsAny(e.ID, "\r\n\x00") || strings.ContainsAny(e.Event, "\r\n") {
+		return nil, errors.New("event id and name must be a single line")
+	}
+
+	var data string
+	switch d := e.Data.(type) {
+	case string:
+		data = d
+	case []byte:
+		data = string(d)
+	default:
+		encoded, err := json.Marshal(d)
+		if err != nil {
+			return nil, err
+		}
+		data = string(encoded)
+	}
+
+	var b bytes.Buffer
+	if e.ID != "" {
+		b.WriteString("id: " + e.ID + "\n")
+	}
+	if e.Event != "" {
+		b.WriteString("event: " + e.Event + "\n")
+	}
+	if e.Retry > 0 {
+		b.WriteString("retry: " + strconv.FormatInt(e.Retry.Milliseconds(), 10) + "\n")
+	}
+	data = strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(data)
+	for _, line := range strings.Split(data, "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+	b.WriteString("\n")
+	return b.Bytes(), nil
+}
+
+// EventStream is the response stream of Context.SSE.
+type EventStream struct {
+	events      *Events
+	w           *bufio.Writer
+	lastEventID string
+}
+
+// LastEventID returns the ID of the last event the client received before
+// reconnecting, or "" on the first connection.
+func (s *EventStream) LastEventID() string {
+	return s.lastEventID
+}
+
+// Send writes event to the client. It fails once the client is gone.
+func (s *EventStream) Send(event Event) error {
+	frame, err := event.encode()
+	if err != nil {
+		return err
+	}
+	return s.write(frame)
+}
+
+// Comment writes a comment line, which clients ignore.
+func (s *EventStream) Comment(text string) error {
+	return s.write([]byte(": " + strings.NewReplacer("\r", " ", "\n", " ").Replace(text) + "\n\n"))
+}
+
+// Done is closed when the application shuts down. Streams that don't use
+// Listen must return once it is.
+func (s *EventStream) Done() <-chan struct{} {
+	return s.events.closing
+}
+
+func (s *EventStream) write(frame []byte) error {
+	if _, err := s.w.Write(frame); err != nil {
+		return err
+	}
+	return s.w.Flush()
+}
+
+// Listen sends the events published to topics until the client goes away
+// or the application shuts down, sending heartbeats while idle. Events the
+// client missed since LastEventID are replayed first, as far as the replay
+// buffer reaches back.
+func (s *EventStream) Listen(topics ...string) error {
+	sub, replay := s.events.subscribe(topics, s.lastEventID)
+	defer s.events.unsubscribe(sub)
+
+	for _, frame := range replay {
+		if err := s.write(frame); err != nil {
+			return err
+		}
+	}
+
+	heartbeat := time.NewTicker(s.events.config.Heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case frame, ok := <-sub.send:
+			if !ok {
+				return ErrStreamBehind
+			}
+			if err := s.write(frame); err != nil {
+				return err
+			}
+			heartbeat.Reset(s.events.config.Heartbeat)
+		case <-heartbeat.C:
+			if err := s.Comment("heartbeat"); err != nil {
+				return err
+			}
+		case <-s.events.closing:
+			return nil
+		}
+	}
+}
+
+// SSE streams Server-Sent Events to the client with fn, which runs after
+// the handler returns: it must not use the Context, so read what it needs
+// from the request first. Errors returned by fn are logged.
+func (c *Context) SSE(fn func(stream *EventStream) error) error {
+	if c.app == nil {
+		return errors.New("sse: context has no application")
+	}
+	events := c.app.events
+	if events.isClosing() {
+		return NewAppError("server is shutting down", http.StatusServiceUnavailable)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	stream := &EventStream{events: events, lastEventID: c.Get("Last-Event-ID")}
+	// The stream is only counted once the writer runs, so a response whose
+	// body is replaced after SSE returns never holds up Shutdown.
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if !events.begin() {
+			return
+		}
+		defer events.streams.Done()
+		stream.w = w
+
+		if events.config.Retry > 0 {
+			retry := "retry: " + strconv.FormatInt(events.config.Retry.Milliseconds(), 10) + "\n\n"
+			if stream.write([]byte(retry)) != nil {
+				return
+			}
+		}
+		if err := fn(stream); err != nil && !errors.Is(err, ErrStreamBehind) {
+			events.logger.Error("Event stream failed: %v", err)
+		}
+	})
+	return nil
+}
+
+// publishedEvent is an event kept in a topic's replay buffer.
+type publishedEvent struct {
+	seq   uint64
+	frame []byte
+}
+
+type subscription struct {
+	topics []string
+	send   chan []byte
+}
+
+// Events fans published events out to the event streams listening to their
+// topic. Publish can be called from anywhere, such as queue job handlers.
+type Events struct {
+	config SSEConfig
+	logger *logger.Logger
+
+	mu            sync.Mutex
+	seq           uint64
+	replay        map[string][]publishedEvent
+	subscriptions map[string]map[*subscription]bool
+
+	closing   chan struct{}
+	closeOnce sync.Once
+	streams   sync.WaitGroup
+}
+
+func newEvents(app *Application) *Events {
+	return &Events{
+		config:        app.config.SSE.withDefaults(),
+		logger:        app.logger,
+		replay:        make(map[string][]publishedEvent),
+		subscriptions: make(map[string]map[*subscription]bool),
+		closing:       make(chan struct{}),
+	}
+}
+
+// Events returns the publisher of the events sent to event streams.
+func (app *Application) Events() *Events {
+	return app.events
+}
+
+// Publish sends payload as an event named topic to the streams listening to
+// topic. Events get increasing IDs, so clients resume where they left off.
+// A stream that can't keep up is ended.
+func (e *Events) Publish(topic string, payload interface{}) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	seq := e.seq + 1
+	frame, err := Event{ID: strconv.FormatUint(seq, 10), Event: topic, Data: payload}.encode()
+	if err != nil {
+		return fmt.Errorf("failed to publish %s event: %w", topic, err)
+	}
+	e.seq = seq
+
+	buffer := append(e.replay[topic], publishedEvent{seq: seq, frame: frame})
+	if len(buffer) > e.config.ReplayBuffer {
+		buffer = append([]publishedEvent(nil), buffer[len(buffer)-e.config.ReplayBuffer:]...)
+	}
+	e.replay[topic] = buffer
+
+	for sub := range e.subscriptions[topic] {
+		select {
+		case sub.send <- frame:
+		default:
+			e.remove(sub)
+			close(sub.send)
+		}
+	}
+	return nil
+}
+
+// Subscribers returns the number of streams listening to topic.
+func (e *Events) Subscribers(topic string) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.subscriptions[topic])
+}
+
+// subscribe registers a stream for topics, returning the buffered events
+// published after lastEventID.
+func (e *Events) subscribe(topics []string, lastEventID string) (*subscription, [][]byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	sub := &subscription{topics: topics, send: make(chan []byte, e.config.SendBuffer)}
+	for _, topic := range topics {
+		if e.subscriptions[topic] == nil {
+			e.subscriptions[topic] = make(map[*subscription]bool)
+		}
+		e.subscriptions[topic][sub] = true
+	}
+
+	// IDs above the current one come from before a restart.
+	last, err := strconv.ParseUint(lastEventID, 10, 64)
+	if err != nil || last >= e.seq {
+		return sub, nil
+	}
+	var missed []publishedEvent
+	for _, topic := range topics {
+		for _, event := range e.replay[topic] {
+			if event.seq > last {
+				missed = append(missed, event)
+			}
+		}
+	}
+	sort.Slice(missed, func(i, j int) bool { return missed[i].seq < missed[j].seq })
+	replay := make([][]byte, len(missed))
+	for i, event := range missed {
+		replay[i] = event.frame
+	}
+	return sub, replay
+}
+
+func (e *Events) unsubscribe(sub *subscription) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.remove(sub)
+}
+
+// remove drops sub from its topics. The caller must hold e.mu.
+func (e *Events) remove(sub *subscription) {
+	for _, topic := range sub.topics {
+		delete(e.subscriptions[topic], sub)
+		if len(e.subscriptions[topic]) == 0 {
+			delete(e.subscriptions, topic)
+		}
+	}
+}
+
+// isClosing reports whether the application is shutting down.
+func (e *Events) isClosing() bool {
+	select {
+	case <-e.closing:
+		return true
+	default:
+		return false
+	}
+}
+
+// begin counts a new stream, unless the application is shutting down.
+func (e *Events) begin() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	select {
+	case <-e.closing:
+		return false
+	default:
+	}
+	e.streams.Add(1)
+	return true
+}
+
+// Shutdown ends the open event streams and waits for them to finish or ctx
+// to end.
+func (e *Events) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	e.closeOnce.Do(func() { close(e.closing) })
+	e.mu.Unlock()
+
+	finished := make(chan struct{})
+	go func() {
+		e.streams.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
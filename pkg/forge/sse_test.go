@@ -0,0 +1,179 @@
+package forge
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type DashboardController struct {
+	Controller
+}
+
+func (c *DashboardController) HandleGetEvents(ctx *Context) error {
+	return ctx.SSE(func(stream *EventStream) error {
+		if err := stream.Send(Event{Event: "hello", Data: "resuming after " + stream.LastEventID()}); err != nil {
+			return err
+		}
+		return stream.Listen("orders")
+	})
+}
+
+func (c *DashboardController) HandleGetBroken(ctx *Context) error {
+	if err := ctx.SSE(func(stream *EventStream) error { return stream.Listen("orders") }); err != nil {
+		return err
+	}
+	return ErrBadRequest
+}
+
+// serveDashboard serves a DashboardController, returning its base URL.
+func serveDashboard(t *testing.T, config *Config) (*Application, string) {
+	app := newTestApp(t, config)
+	require.NoError(t, app.RegisterController(&DashboardController{}))
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go app.Serve(ln)
+	t.Cleanup(func() { app.ShutdownWithContext(context.Background()) })
+	return app, "http://" + ln.Addr().String()
+}
+
+// openStream connects to an event stream, resuming after lastEventID if
+// set.
+func openStream(t *testing.T, url, lastEventID string) *bufio.Reader {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	var resp *http.Response
+	require.Eventually(t, func() bool {
+		resp, err = http.DefaultClient.Do(req)
+		return err == nil
+	}, 5*time.Second, 20*time.Millisecond, "server did not come up")
+	t.Cleanup(func() { resp.Body.Close() })
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "no-cache", resp.Header.Get("Cache-Control"))
+	return bufio.NewReader(resp.Body)
+}
+
+// readEvent reads the fields of the next event, joining data lines and
+// keeping comments under "".
+func readEvent(t *testing.T, r *bufio.Reader) map[string]string {
+	t.Helper()
+	event := make(map[string]string)
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return event
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		if previous, ok := event[field]; ok && field == "data" {
+			value = previous + "\n" + value
+		}
+		event[field] = value
+	}
+}
+
+func TestEventEncode(t *testing.T) {
+	frame, err := Event{ID: "7", Event: "update", Data: "line one\r\nline two", Retry: 3 * time.Second}.encode()
+	require.NoError(t, err)
+	assert.Equal(t, "id: 7\nevent: update\nretry: 3000\ndata: line one\ndata: line two\n\n", string(frame))
+
+	frame, err = Event{Data: map[string]int{"count": 2}}.encode()
+	require.NoError(t, err)
+	assert.Equal(t, "data: {\"count\":2}\n\n", string(frame))
+
+	_, err = Event{ID: "1\nevent: spoofed"}.encode()
+	assert.Error(t, err)
+}
+
+func TestSSEPublishAndResume(t *testing.T) {
+	app, url := serveDashboard(t, &Config{SSE: SSEConfig{Retry: 2 * time.Second}})
+
+	stream := openStream(t, url+"/dashboard/events", "")
+	assert.Equal(t, map[string]string{"retry": "2000"}, readEvent(t, stream))
+	assert.Equal(t, map[string]string{"event": "hello", "data": "resuming after "}, readEvent(t, stream))
+
+	require.Eventually(t, func() bool { return app.Events().Subscribers("orders") == 1 }, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, app.Events().Publish("orders", map[string]int{"id": 1}))
+	require.NoError(t, app.Events().Publish("users", map[string]int{"id": 9}))
+	require.NoError(t, app.Events().Publish("orders", map[string]int{"id": 2}))
+
+	assert.Equal(t, map[string]string{"id": "1", "event": "orders", "data": `{"id":1}`}, readEvent(t, stream))
+	assert.Equal(t, map[string]string{"id": "3", "event": "orders", "data": `{"id":2}`}, readEvent(t, stream), "other topics are not sent")
+
+	// A reconnecting client gets the events it missed.
+	require.NoError(t, app.Events().Publish("orders", map[string]int{"id": 3}))
+	resumed := openStream(t, url+"/dashboard/events", "1")
+	readEvent(t, resumed)
+	assert.Equal(t, map[string]string{"event": "hello", "data": "resuming after 1"}, readEvent(t, resumed))
+	assert.Equal(t, "3", readEvent(t, resumed)["id"])
+	assert.Equal(t, "4", readEvent(t, resumed)["id"])
+
+	assert.Error(t, app.Events().Publish("orders", func() {}), "payload must encode")
+}
+
+func TestSSEReplayBufferIsBounded(t *testing.T) {
+	app, url := serveDashboard(t, &Config{SSE: SSEConfig{ReplayBuffer: 2}})
+	for i := 0; i < 5; i++ {
+		require.NoError(t, app.Events().Publish("orders", i))
+	}
+
+	stream := openStream(t, url+"/dashboard/events", "1")
+	readEvent(t, stream)
+	assert.Equal(t, "4", readEvent(t, stream)["id"])
+	assert.Equal(t, "5", readEvent(t, stream)["id"])
+}
+
+func TestSSEHeartbeat(t *testing.T) {
+	_, url := serveDashboard(t, &Config{SSE: SSEConfig{Heartbeat: 20 * time.Millisecond}})
+
+	stream := openStream(t, url+"/dashboard/events", "")
+	readEvent(t, stream)
+	assert.Equal(t, map[string]string{"": "heartbeat"}, readEvent(t, stream))
+}
+
+func TestSSEReplacedBodyDoesNotBlockShutdown(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	require.NoError(t, app.RegisterController(&DashboardController{}))
+
+	status, _ := getBody(t, app, "/dashboard/broken")
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, app.Events().Shutdown(ctx))
+
+	assert.Error(t, (&Context{}).SSE(func(*EventStream) error { return nil }), "SSE needs an application")
+}
+
+func TestSSEShutdown(t *testing.T) {
+	app, url := serveDashboard(t, &Config{})
+
+	stream := openStream(t, url+"/dashboard/events", "")
+	readEvent(t, stream)
+	require.Eventually(t, func() bool { return app.Events().Subscribers("orders") == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, app.ShutdownWithContext(ctx))
+	assert.Zero(t, app.Events().Subscribers("orders"))
+
+	_, err := io.ReadAll(stream)
+	assert.NoError(t, err, "the stream ends cleanly")
+}
@@ -0,0 +1,220 @@
+package forge
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// TLSConfig enables HTTPS on the main listener. Certificates are re-read from
+// disk on SIGHUP without dropping open connections.
+type TLSConfig struct {
+	CertFile   string `yaml:"cert_file"`
+	KeyFile    string `yaml:"key_file"`
+	MinVersion string `yaml:"min_version"` // 1.0, 1.1, 1.2 (default) or 1.3
+
+	// ClientCAFile enables mutual TLS: client certificates are verified
+	// against the CAs in this PEM bundle.
+	ClientCAFile string `yaml:"client_ca_file"`
+	// ClientAuth is "require" (default) or "optional" when ClientCAFile is set.
+	ClientAuth string `yaml:"client_auth"`
+
+	// RedirectAddr starts a plain HTTP listener, e.g. ":80", that redirects
+	// every request to HTTPS.
+	RedirectAddr string `yaml:"redirect_addr"`
+}
+
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" && c.KeyFile != ""
+}
+
+// certReloader serves the current certificate and client CA pool and swaps
+// them atomically on Reload.
+type certReloader struct {
+	config     TLSConfig
+	minVersion uint16
+	clientAuth tls.ClientAuthType
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	clientCA *x509.CertPool
+}
+
+func newCertReloader(config TLSConfig) (*certReloader, error) {
+	r := &certReloader{
+		config:     config,
+		clientAuth: tls.NoClientCert,
+	}
+
+	switch config.MinVersion {
+	case "1.0":
+		r.minVersion = tls.VersionTLS10
+	case "1.1":
+		r.minVersion = tls.VersionTLS11
+	case "", "1.2":
+		r.minVersion = tls.VersionTLS12
+	case "1.3":
+		r.minVersion = tls.VersionTLS13
+	default:
+		return nil, fmt.Errorf("unsupported TLS min_version: %s", config.MinVersion)
+	}
+
+	if config.ClientCAFile != "" {
+		switch config.ClientAuth {
+		case "", "require":
+			r.clientAuth = tls.RequireAndVerifyClientCert
+		case "optional":
+			r.clientAuth = tls.VerifyClientCertIfGiven
+		default:
+			return nil, fmt.Errorf("unsupported TLS client_auth: %s", config.ClientAuth)
+		}
+	}
+
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload re-reads the certificate, key and client CA bundle from disk. The
+// previous material stays in use if anything fails to load.
+func (r *certReloader) Reload() error {
+	cert, err := tls.LoadX509KeyPair(r.config.CertFile, r.config.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	var pool *x509.CertPool
+	if r.config.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.config.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read client CA file: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in client CA file %s", r.config.ClientCAFile)
+		}
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.clientCA = pool
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *certReloader) tlsConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: r.minVersion,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+			return &tls.Config{
+				MinVersion:   r.minVersion,
+				Certificates: []tls.Certificate{*r.cert},
+				ClientCAs:    r.clientCA,
+				ClientAuth:   r.clientAuth,
+			}, nil
+		},
+	}
+}
+
+// tlsListener wraps ln in TLS, starts the optional HTTP redirect listener
+// and reloads certificates on SIGHUP until shutdown.
+func (app *Application) tlsListener(ln net.Listener) (net.Listener, error) {
+	config := app.config.Server.TLS
+
+	reloader, err := newCertReloader(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.RedirectAddr != "" {
+		_, port, _ := net.SplitHostPort(ln.Addr().String())
+		if err := app.startHTTPSRedirect(config.RedirectAddr, port); err != nil {
+			return nil, err
+		}
+	}
+
+	go app.reloadCertificatesOnSIGHUP(reloader)
+
+	return tls.NewListener(ln, reloader.tlsConfig()), nil
+}
+
+func (app *Application) reloadCertificatesOnSIGHUP(reloader *certReloader) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-hup:
+			if err := reloader.Reload(); err != nil {
+				app.logger.Error("Failed to reload TLS certificates: %v", err)
+				continue
+			}
+			app.logger.Info("TLS certificates reloaded")
+		case <-app.closing:
+			return
+		}
+	}
+}
+
+// startHTTPSRedirect serves permanent redirects to the HTTPS listener on httpsPort.
+func (app *Application) startHTTPSRedirect(addr, httpsPort string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to start HTTPS redirect listener: %w", err)
+	}
+
+	server := &http.Server{
+		Handler:           httpsRedirectHandler(httpsPort),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	app.mu.Lock()
+	app.redirectServer = server
+	app.mu.Unlock()
+
+	go func() {
+		if err := server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			app.logger.Error("HTTPS redirect listener failed: %v", err)
+		}
+	}()
+
+	app.logger.Info("Redirecting HTTP on %s to HTTPS port %s", addr, httpsPort)
+	return nil
+}
+
+func httpsRedirectHandler(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if httpsPort != "" && httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+	})
+}
+
+func (app *Application) shutdownRedirect(ctx context.Context) error {
+	app.mu.RLock()
+	server := app.redirectServer
+	app.mu.RUnlock()
+
+	if server == nil {
+		return nil
+	}
+	return server.Shutdown(ctx)
+}
@@ -0,0 +1,188 @@
+package forge
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	tls  tls.Certificate
+}
+
+// issueTestCert creates a certificate signed by parent, or a self-signed CA
+// when parent is nil.
+func issueTestCert(t *testing.T, cn string, parent *testCert) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCert{
+		cert: cert,
+		key:  key,
+		tls:  tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key},
+	}
+}
+
+func (c *testCert) write(t *testing.T, dir, name string) (certFile, keyFile string) {
+	t.Helper()
+
+	keyDER, err := x509.MarshalECPrivateKey(c.key)
+	require.NoError(t, err)
+
+	certFile = filepath.Join(dir, name+".crt")
+	keyFile = filepath.Join(dir, name+".key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return certFile, keyFile
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := issueTestCert(t, "Test CA", nil)
+	caFile, _ := ca.write(t, dir, "ca")
+	certFile, keyFile := issueTestCert(t, "server", ca).write(t, dir, "server")
+	client := issueTestCert(t, "billing-service", ca)
+
+	app := newTestApp(t, &Config{
+		Server: ServerConfig{
+			TLS: TLSConfig{
+				CertFile:     certFile,
+				KeyFile:      keyFile,
+				MinVersion:   "1.2",
+				ClientCAFile: caFile,
+			},
+		},
+	})
+	app.Get().Get("/whoami", func(c *fiber.Ctx) error {
+		cert := NewContext(c, app).ClientCertificate()
+		if cert == nil {
+			return c.SendStatus(http.StatusUnauthorized)
+		}
+		return c.SendString(cert.Subject.CommonName)
+	})
+
+	tcp, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	ln, err := app.tlsListener(tcp)
+	require.NoError(t, err)
+	go app.server.Listener(ln)
+	t.Cleanup(func() { app.ShutdownWithContext(context.Background()) })
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	url := "https://" + ln.Addr().String() + "/whoami"
+
+	httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		RootCAs:      roots,
+		Certificates: []tls.Certificate{client.tls},
+	}}}
+	resp, err := httpClient.Get(url)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "billing-service", string(body))
+
+	anonymous := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}}
+	_, err = anonymous.Get(url)
+	assert.Error(t, err)
+}
+
+func TestCertReloaderSwapsCertificate(t *testing.T) {
+	dir := t.TempDir()
+	ca := issueTestCert(t, "Test CA", nil)
+	first := issueTestCert(t, "first", ca)
+	certFile, keyFile := first.write(t, dir, "server")
+
+	reloader, err := newCertReloader(TLSConfig{CertFile: certFile, KeyFile: keyFile})
+	require.NoError(t, err)
+
+	config, err := reloader.tlsConfig().GetConfigForClient(nil)
+	require.NoError(t, err)
+	assert.Equal(t, first.cert.Raw, config.Certificates[0].Certificate[0])
+
+	second := issueTestCert(t, "second", ca)
+	second.write(t, dir, "server")
+	require.NoError(t, reloader.Reload())
+
+	config, err = reloader.tlsConfig().GetConfigForClient(nil)
+	require.NoError(t, err)
+	assert.Equal(t, second.cert.Raw, config.Certificates[0].Certificate[0])
+
+	require.NoError(t, os.WriteFile(certFile, []byte("garbage"), 0600))
+	assert.Error(t, reloader.Reload())
+
+	config, err = reloader.tlsConfig().GetConfigForClient(nil)
+	require.NoError(t, err)
+	assert.Equal(t, second.cert.Raw, config.Certificates[0].Certificate[0])
+}
+
+func TestCertReloaderRejectsInvalidSettings(t *testing.T) {
+	_, err := newCertReloader(TLSConfig{CertFile: "a", KeyFile: "b", MinVersion: "2.0"})
+	assert.ErrorContains(t, err, "min_version")
+
+	_, err = newCertReloader(TLSConfig{CertFile: "a", KeyFile: "b", ClientCAFile: "ca", ClientAuth: "sometimes"})
+	assert.ErrorContains(t, err, "client_auth")
+}
+
+func TestHTTPSRedirectHandler(t *testing.T) {
+	tests := []struct {
+		port     string
+		target   string
+		location string
+	}{
+		{"443", "http://example.com/orders?page=2", "https://example.com/orders?page=2"},
+		{"8443", "http://example.com:8080/orders", "https://example.com:8443/orders"},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		httpsRedirectHandler(tt.port).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+		assert.Equal(t, http.StatusMovedPermanently, rec.Code)
+		assert.Equal(t, tt.location, rec.Header().Get("Location"))
+	}
+}
@@ -0,0 +1,124 @@
+//go:build !windows
+
+package forge
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// upgradeOnSIGUSR2 starts a new copy of the executable on SIGUSR2 and hands
+// it ln. Once the new process is serving, this one shuts down gracefully, so
+// no connection is refused while the binary is replaced.
+func (app *Application) upgradeOnSIGUSR2(ln net.Listener) {
+	usr2 := make(chan os.Signal, 1)
+	signal.Notify(usr2, syscall.SIGUSR2)
+	defer signal.Stop(usr2)
+
+	for {
+		select {
+		case <-usr2:
+			app.logger.Info("Received SIGUSR2, starting upgraded process")
+			if err := app.upgrade(ln); err != nil {
+				app.logger.Error("Upgrade failed, keeping the current process: %v", err)
+				continue
+			}
+			if err := app.Shutdown(); err != nil {
+				app.logger.Error("Failed to shut down after upgrade: %v", err)
+			}
+			return
+		case <-app.closing:
+			return
+		}
+	}
+}
+
+// upgrade re-executes the binary with ln as file descriptor 3 and waits
+// until the child reports that it is serving.
+func (app *Application) upgrade(ln net.Listener) error {
+	filer, ok := ln.(interface{ File() (*os.File, error) })
+	if !ok {
+		return fmt.Errorf("listener %T cannot be handed over", ln)
+	}
+	file, err := filer.File()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// The socket file must outlive this process's listener.
+	unix, _ := ln.(*net.UnixListener)
+	if unix != nil {
+		unix.SetUnlinkOnClose(false)
+	}
+
+	if err := app.startUpgrade(file); err != nil {
+		if unix != nil {
+			unix.SetUnlinkOnClose(true)
+		}
+		return err
+	}
+	return nil
+}
+
+func (app *Application) startUpgrade(listener *os.File) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	ready, readyWriter, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer ready.Close()
+
+	var env []string
+	for _, kv := range os.Environ() {
+		if !strings.HasPrefix(kv, ListenFDEnv+"=") && !strings.HasPrefix(kv, upgradeReadyEnv+"=") {
+			env = append(env, kv)
+		}
+	}
+
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.Env = append(env, ListenFDEnv+"=3", upgradeReadyEnv+"=4")
+	cmd.ExtraFiles = []*os.File{listener, readyWriter}
+
+	err = cmd.Start()
+	readyWriter.Close()
+	if err != nil {
+		return err
+	}
+	app.logger.Info("Started upgraded process %d", cmd.Process.Pid)
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+	notified := make(chan bool, 1)
+	go func() {
+		n, _ := ready.Read(make([]byte, 1))
+		notified <- n == 1
+	}()
+
+	select {
+	case ok := <-notified:
+		if ok {
+			return nil
+		}
+		return fmt.Errorf("upgraded process %d exited before serving", cmd.Process.Pid)
+	case err := <-exited:
+		return fmt.Errorf("upgraded process exited before serving: %v", err)
+	case <-time.After(app.shutdownTimeout()):
+		cmd.Process.Kill()
+		return errors.New("timed out waiting for the upgraded process")
+	}
+}
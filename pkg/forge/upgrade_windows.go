@@ -0,0 +1,8 @@
+//go:build windows
+
+package forge
+
+import "net"
+
+// upgradeOnSIGUSR2 is a no-op: Windows has no SIGUSR2 or descriptor passing.
+func (app *Application) upgradeOnSIGUSR2(ln net.Listener) {}
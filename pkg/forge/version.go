@@ -0,0 +1,54 @@
+package forge
+
+import (
+	"strings"
+)
+
+// APIVersion mounts controllers under a versioned prefix such as /api/v1 so
+// several versions of the same controller can be served side by side.
+type APIVersion struct {
+	app  *Application
+	name string
+}
+
+// Version returns the API version with the given name, e.g. app.Version("v1").
+func (app *Application) Version(name string) *APIVersion {
+	return &APIVersion{
+		app:  app,
+		name: strings.Trim(name, "/"),
+	}
+}
+
+// Name returns the version name, e.g. v1.
+func (v *APIVersion) Name() string {
+	return v.name
+}
+
+// Prefix returns the path every controller of this version is mounted under.
+func (v *APIVersion) Prefix() string {
+	return joinPath(v.app.config.Server.BasePath, v.name)
+}
+
+// RegisterController mounts the controller under this version, ignoring any
+// Version() method the controller declares itself.
+func (v *APIVersion) RegisterController(controller interface{}) error {
+	return v.app.registerController(controller, controllerEntry{version: v.name})
+}
+
+// GenerateOpenAPI builds an OpenAPI document containing only this version's routes.
+func (v *APIVersion) GenerateOpenAPI() (*OpenAPISpec, error) {
+	return v.app.generateOpenAPI(v.name)
+}
+
+// joinPath joins URL path segments into a clean absolute path.
+func joinPath(parts ...string) string {
+	var segments []string
+	for _, part := range parts {
+		for _, segment := range strings.Split(part, "/") {
+			if segment != "" {
+				segments = append(segments, segment)
+			}
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
@@ -0,0 +1,83 @@
+package forge
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type WidgetController struct {
+	Controller
+}
+
+func (c *WidgetController) HandleGetWidgets(ctx *Context) error {
+	return ctx.SendString("widgets v1")
+}
+
+type WidgetV2Controller struct {
+	Controller
+}
+
+func (c *WidgetV2Controller) Version() string {
+	return "v2"
+}
+
+func (c *WidgetV2Controller) HandleGetWidgets(ctx *Context) error {
+	return ctx.SendString("widgets v2")
+}
+
+func getBody(t *testing.T, app *Application, path string) (int, string) {
+	t.Helper()
+	resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestRegisterControllerHonoursBasePath(t *testing.T) {
+	app := newTestApp(t, &Config{Server: ServerConfig{BasePath: "/api"}})
+	app.RegisterController(&WidgetController{})
+
+	status, body := getBody(t, app, "/api/widget/widgets")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, "widgets v1", body)
+
+	status, _ = getBody(t, app, "/widget/widgets")
+	assert.Equal(t, 404, status)
+}
+
+func TestAPIVersionsSideBySide(t *testing.T) {
+	app := newTestApp(t, &Config{Server: ServerConfig{BasePath: "/api"}})
+	app.Version("v1").RegisterController(&WidgetController{})
+	app.RegisterController(&WidgetV2Controller{})
+
+	status, body := getBody(t, app, "/api/v1/widget/widgets")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, "widgets v1", body)
+
+	status, body = getBody(t, app, "/api/v2/widgetv2/widgets")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, "widgets v2", body)
+
+	v1, err := app.Version("v1").GenerateOpenAPI()
+	require.NoError(t, err)
+	assert.Equal(t, "v1", v1.Info.Version)
+	assert.Contains(t, v1.Paths, "/api/v1/widget/widgets")
+	assert.NotContains(t, v1.Paths, "/api/v2/widgetv2/widgets")
+
+	all, err := app.GenerateOpenAPI()
+	require.NoError(t, err)
+	assert.Len(t, all.Paths, 2)
+}
+
+func TestOpenAPIPathParameters(t *testing.T) {
+	path, params := openAPIPath("/api/users/:id/posts/:postId")
+	assert.Equal(t, "/api/users/{id}/posts/{postId}", path)
+	require.Len(t, params, 2)
+	assert.Equal(t, "id", params[0].Name)
+	assert.Equal(t, "postId", params[1].Name)
+}
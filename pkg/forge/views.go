@@ -0,0 +1,268 @@
+package forge
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/gofiber/fiber/v2"
+)
+
+// CSRFContextKey is the local the csrfToken and csrfField view helpers read
+// the CSRF token from. Set it as the ContextKey of Fiber's csrf middleware.
+const CSRFContextKey = "csrf"
+
+// Views renders html/template views from the view directory or a file
+// system set with SetFS. Names are paths relative to the directory without
+// the extension, e.g. "users/show" for templates/users/show.gohtml.
+//
+// A view is rendered inside a layout, which includes it with
+// {{template "content" .}}; blocks of the layout such as
+// {{block "title" .}} can be overridden with {{define "title"}} in the view.
+// Templates under partials/ can be included by every view and layout, e.g.
+// {{template "partials/nav" .}}.
+type Views struct {
+	app       *Application
+	fsys      fs.FS
+	dir       string // on disk, empty for a custom file system
+	extension string
+	layout    string
+	funcs     template.FuncMap
+	reload    bool
+
+	mu        sync.RWMutex
+	pages     map[string]*template.Template
+	watchOnce sync.Once
+}
+
+func newViews(app *Application) *Views {
+	config := app.config.View
+	dir := config.Directory
+	if dir == "" {
+		dir = "templates"
+	}
+	extension := config.Extension
+	if extension == "" {
+		extension = ".gohtml"
+	}
+
+	v := &Views{
+		app:       app,
+		fsys:      os.DirFS(dir),
+		dir:       dir,
+		extension: extension,
+		layout:    config.Layout,
+		funcs:     make(template.FuncMap),
+		reload:    app.IsDevelopment() || config.Reload,
+		pages:     make(map[string]*template.Template),
+	}
+	v.funcs["url"] = app.URL
+	v.funcs["asset"] = v.asset
+	// Bound to the request when a view is executed.
+	v.funcs["csrfToken"] = func() string { return "" }
+	v.funcs["csrfField"] = func() template.HTML { return "" }
+	return v
+}
+
+// Views returns the view engine used by Context.View.
+func (app *Application) Views() *Views {
+	return app.views
+}
+
+// SetFS reads the views from fsys instead of the view directory, e.g. from
+// an embed.FS in production builds. If fsys contains the view directory,
+// as with //go:embed templates, views are read from there. Views read from
+// fsys are never reloaded.
+func (v *Views) SetFS(fsys fs.FS) *Views {
+	if info, err := fs.Stat(fsys, filepath.ToSlash(v.dir)); err == nil && info.IsDir() {
+		if sub, err := fs.Sub(fsys, filepath.ToSlash(v.dir)); err == nil {
+			fsys = sub
+		}
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.fsys = fsys
+	v.dir = ""
+	v.pages = make(map[string]*template.Template)
+	return v
+}
+
+// AddFuncs makes funcs available to every view. It must be called before
+// the first view is rendered.
+func (v *Views) AddFuncs(funcs template.FuncMap) *Views {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for name, fn := range funcs {
+		v.funcs[name] = fn
+	}
+	v.pages = make(map[string]*template.Template)
+	return v
+}
+
+// View renders the named view as HTML inside the configured layout, or
+// inside layouts[0] if given; an empty layout renders the view alone.
+func (c *Context) View(name string, data interface{}, layouts ...string) error {
+	layout := c.app.views.layout
+	if len(layouts) > 0 {
+		layout = layouts[0]
+	}
+
+	page, err := c.app.views.page(name, layout)
+	if err != nil {
+		return err
+	}
+	page, err = page.Clone()
+	if err != nil {
+		return err
+	}
+	token, _ := c.Locals(CSRFContextKey).(string)
+	page.Funcs(template.FuncMap{
+		"csrfToken": func() string { return token },
+		"csrfField": func() template.HTML {
+			return template.HTML(`<input type="hidden" name="_csrf" value="` + template.HTMLEscapeString(token) + `">`)
+		},
+	})
+
+	var body bytes.Buffer
+	if err := page.Execute(&body, data); err != nil {
+		return fmt.Errorf("view %s: %w", name, err)
+	}
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.Send(body.Bytes())
+}
+
+// page returns the parsed view inside layout, parsing it on first use.
+func (v *Views) page(name, layout string) (*template.Template, error) {
+	if v.reload && v.dir != "" {
+		v.watchOnce.Do(v.watch)
+	}
+
+	key := name + "\x00" + layout
+	v.mu.RLock()
+	page, ok := v.pages[key]
+	v.mu.RUnlock()
+	if ok {
+		return page, nil
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if page, ok := v.pages[key]; ok {
+		return page, nil
+	}
+	page, err := v.parse(name, layout)
+	if err != nil {
+		return nil, err
+	}
+	v.pages[key] = page
+	return page, nil
+}
+
+// parse builds the template set of a page: the partials, the layout and
+// the view as "content". The caller must hold v.mu.
+func (v *Views) parse(name, layout string) (*template.Template, error) {
+	root := "content"
+	if layout != "" {
+		root = layout
+	}
+	page := template.New("page:" + name).Funcs(v.funcs)
+
+	partials, err := fs.Glob(v.fsys, "partials/*"+v.extension)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range partials {
+		if err := v.parseFile(page, strings.TrimSuffix(file, v.extension), file); err != nil {
+			return nil, err
+		}
+	}
+	if layout != "" {
+		if err := v.parseFile(page, layout, layout+v.extension); err != nil {
+			return nil, err
+		}
+	}
+	if err := v.parseFile(page, "content", name+v.extension); err != nil {
+		return nil, err
+	}
+	return page.Lookup(root), nil
+}
+
+func (v *Views) parseFile(page *template.Template, name, file string) error {
+	source, err := fs.ReadFile(v.fsys, path.Clean(file))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("view %s not found", strings.TrimSuffix(file, v.extension))
+		}
+		return err
+	}
+	if _, err := page.New(name).Parse(string(source)); err != nil {
+		return fmt.Errorf("view %w", err)
+	}
+	return nil
+}
+
+// asset returns the URL of a static asset below ViewConfig.Assets.
+func (v *Views) asset(file string) string {
+	prefix := v.app.config.View.Assets
+	if prefix == "" {
+		prefix = "/assets"
+	}
+	return joinPath(prefix, file)
+}
+
+// watch drops the parsed views whenever a file of the view directory
+// changes, until the application shuts down.
+func (v *Views) watch() {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		v.app.logger.Error("Failed to watch views: %v", err)
+		return
+	}
+	addDirs := func(root string) {
+		filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err == nil && entry.IsDir() {
+				if err := watcher.Add(path); err != nil {
+					v.app.logger.Error("Failed to watch %s: %v", path, err)
+				}
+			}
+			return nil
+		})
+	}
+	addDirs(v.dir)
+
+	go func() {
+		defer watcher.Close()
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if event.Has(fsnotify.Create) {
+					if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+						addDirs(event.Name)
+					}
+				}
+				v.mu.Lock()
+				v.pages = make(map[string]*template.Template)
+				v.mu.Unlock()
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				v.app.logger.Error("Failed to watch views: %v", err)
+			case <-v.app.closing:
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,129 @@
+package forge
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeViews(t *testing.T, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join("templates", name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+var testViews = map[string]string{
+	"layouts/main.gohtml": `<title>{{block "title" .}}Forge{{end}}</title>{{template "partials/nav" .}}<main>{{template "content" .}}</main>`,
+	"partials/nav.gohtml": `<nav><a href="{{url "ArticleController.Show" 7}}">article</a><link href="{{asset "css/app.css"}}"></nav>`,
+	"users/show.gohtml":   `{{define "title"}}User {{.Name}}{{end}}<h1>{{.Name}}</h1><form>{{csrfField}}</form>`,
+}
+
+func viewApp(t *testing.T, config *Config) *Application {
+	t.Helper()
+	app := newTestApp(t, config)
+	writeViews(t, testViews)
+	require.NoError(t, app.Resource("/articles", &ArticleController{}))
+	app.Group("/").Get("/users/show", func(ctx *Context) error {
+		ctx.Locals(CSRFContextKey, `tok"en`)
+		layouts := []string{}
+		if ctx.Query("bare") != "" {
+			layouts = append(layouts, "")
+		}
+		return ctx.View("users/show", H{"Name": "<Ada>"}, layouts...)
+	})
+	return app
+}
+
+func TestViewRendersLayoutPartialsAndHelpers(t *testing.T) {
+	app := viewApp(t, &Config{Environment: EnvProduction, View: ViewConfig{Layout: "layouts/main"}})
+
+	resp, body := renderRequest(t, app, http.MethodGet, "/users/show", "", "", "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode, body)
+	assert.Equal(t, "text/html; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Equal(t, `<title>User &lt;Ada&gt;</title>`+
+		`<nav><a href="/articles/7">article</a><link href="/assets/css/app.css"></nav>`+
+		`<main><h1>&lt;Ada&gt;</h1><form><input type="hidden" name="_csrf" value="tok&#34;en"></form></main>`, body)
+
+	_, body = renderRequest(t, app, http.MethodGet, "/users/show?bare=1", "", "", "")
+	assert.Equal(t, `<h1>&lt;Ada&gt;</h1><form><input type="hidden" name="_csrf" value="tok&#34;en"></form>`, body)
+}
+
+func TestViewErrors(t *testing.T) {
+	app := viewApp(t, &Config{Environment: EnvProduction, View: ViewConfig{}})
+
+	_, err := app.Views().page("users/missing", "")
+	assert.EqualError(t, err, "view users/missing not found")
+
+	_, err = app.Views().page("users/show", "layouts/admin")
+	assert.EqualError(t, err, "view layouts/admin not found")
+
+	writeViews(t, map[string]string{"broken.gohtml": `{{ .Name `})
+	_, err = app.Views().page("broken", "")
+	assert.ErrorContains(t, err, "view template: content:1")
+}
+
+func TestViewCacheAndReload(t *testing.T) {
+	app := viewApp(t, &Config{Environment: EnvProduction, View: ViewConfig{}})
+	_, body := renderRequest(t, app, http.MethodGet, "/users/show", "", "", "")
+	writeViews(t, map[string]string{"users/show.gohtml": "changed"})
+	_, cached := renderRequest(t, app, http.MethodGet, "/users/show", "", "", "")
+	assert.Equal(t, body, cached, "cached views are not re-read")
+	assert.False(t, app.Views().reload, "a zero ViewConfig caches outside development")
+
+	app = viewApp(t, &Config{Environment: EnvProduction, View: ViewConfig{Reload: true}})
+	assert.True(t, app.Views().reload)
+
+	app = viewApp(t, &Config{Environment: EnvDevelopment, View: ViewConfig{}})
+	_, body = renderRequest(t, app, http.MethodGet, "/users/show", "", "", "")
+	assert.Contains(t, body, "<h1>")
+	t.Cleanup(func() { app.Shutdown() })
+
+	writeViews(t, map[string]string{"users/show.gohtml": "changed {{.Name}}"})
+	assert.Eventually(t, func() bool {
+		_, body := renderRequest(t, app, http.MethodGet, "/users/show", "", "", "")
+		return body == "changed &lt;Ada&gt;"
+	}, 2*time.Second, 20*time.Millisecond, "development views are re-parsed on change")
+}
+
+func TestViewsFromFS(t *testing.T) {
+	app := newTestApp(t, &Config{Environment: EnvDevelopment, View: ViewConfig{Extension: ".html"}})
+	app.Views().SetFS(fstest.MapFS{
+		"templates/home.html":            {Data: []byte(`{{template "partials/footer" .}}{{upper .}}`)},
+		"templates/partials/footer.html": {Data: []byte(`footer `)},
+	}).AddFuncs(map[string]interface{}{"upper": func(s string) string { return s + "!" }})
+	app.Group("/").Get("/home", func(ctx *Context) error {
+		return ctx.View("home", "hi")
+	})
+
+	_, body := renderRequest(t, app, http.MethodGet, "/home", "", "", "")
+	assert.Equal(t, "footer hi!", body)
+}
+
+func TestURL(t *testing.T) {
+	app := newTestApp(t, &Config{Server: ServerConfig{BasePath: "/api"}})
+	require.NoError(t, app.Resource("/users/:user_id/articles", &ArticleController{}))
+
+	url, err := app.URL("ArticleController.Show", 3, "a b")
+	require.NoError(t, err)
+	assert.Equal(t, "/api/users/3/articles/a%20b", url)
+
+	url, err = app.URL("ArticleController.Index", 3)
+	require.NoError(t, err)
+	assert.Equal(t, "/api/users/3/articles", url)
+
+	_, err = app.URL("ArticleController.Show", 3)
+	assert.EqualError(t, err, `route "ArticleController.Show" needs a value for :id`)
+	_, err = app.URL("ArticleController.Index", 3, 4)
+	assert.EqualError(t, err, `route "ArticleController.Index" takes 1 parameters, got 2`)
+	_, err = app.URL("ArticleController.Missing")
+	assert.EqualError(t, err, `no route named "ArticleController.Missing"`)
+}
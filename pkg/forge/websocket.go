@@ -0,0 +1,549 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/BisiOlaYemi/forge/pkg/forge/logger"
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+// WebSocket defaults, used when the matching WebSocketConfig field is zero.
+const (
+	DefaultWSMaxMessageSize = 64 << 10
+	DefaultWSPingInterval   = 30 * time.Second
+	DefaultWSPongTimeout    = 60 * time.Second
+	DefaultWSWriteTimeout   = 10 * time.Second
+	DefaultWSSendBuffer     = 256
+)
+
+// ErrWSClosed is returned when sending on a closed WebSocket connection.
+var ErrWSClosed = errors.New("websocket connection closed")
+
+// WebSocketConfig limits the WebSocket connections served by HandleWs*
+// handlers. Zero limits are unlimited.
+type WebSocketConfig struct {
+	MaxConnections        int           `yaml:"max_connections"`
+	MaxConnectionsPerUser int           `yaml:"max_connections_per_user"`
+	MaxMessageSize        int64         `yaml:"max_message_size"` // bytes, defaults to 64KB
+	PingInterval          time.Duration `yaml:"ping_interval"`    // defaults to 30s
+	PongTimeout           time.Duration `yaml:"pong_timeout"`     // defaults to 60s
+	WriteTimeout          time.Duration `yaml:"write_timeout"`    // defaults to 10s
+	SendBuffer            int           `yaml:"send_buffer"`      // queued messages per connection, defaults to 256
+}
+
+func (c WebSocketConfig) withDefaults() WebSocketConfig {
+	if c.MaxMessageSize == 0 {
+		c.MaxMessageSize = DefaultWSMaxMessageSize
+	}
+	if c.PingInterval == 0 {
+		c.PingInterval = DefaultWSPingInterval
+	}
+	if c.PongTimeout == 0 {
+		c.PongTimeout = DefaultWSPongTimeout
+	}
+	if c.WriteTimeout == 0 {
+		c.WriteTimeout = DefaultWSWriteTimeout
+	}
+	if c.SendBuffer == 0 {
+		c.SendBuffer = DefaultWSSendBuffer
+	}
+	return c
+}
+
+type wsMessage struct {
+	messageType int
+	data        []byte
+}
+
+// WSConn is a WebSocket connection served by a HandleWs* handler. The
+// handler reads with ReadMessage or ReadJSON until they fail; writes are
+// queued with Send and SendJSON and may come from any goroutine.
+type WSConn struct {
+	conn   *websocket.Conn
+	hub    *Hub
+	id     uint64
+	userID string
+	config WebSocketConfig
+
+	send      chan wsMessage
+	closed    chan struct{}
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+var wsConnIDs atomic.Uint64
+
+// ID identifies the connection within the process.
+func (c *WSConn) ID() uint64 {
+	return c.id
+}
+
+// UserID returns the user authenticated by middleware.RequireAuth before
+// the upgrade, or "" for anonymous connections.
+func (c *WSConn) UserID() string {
+	return c.userID
+}
+
+// Claims returns the JWT claims set by middleware.RequireAuth, or nil.
+func (c *WSConn) Claims() map[string]interface{} {
+	claims, _ := c.conn.Locals("claims").(map[string]interface{})
+	return claims
+}
+
+// Locals returns a value stored by middleware before the upgrade.
+func (c *WSConn) Locals(key string) interface{} {
+	return c.conn.Locals(key)
+}
+
+// Param returns a path parameter of the upgrade request.
+func (c *WSConn) Param(name string) string {
+	return c.conn.Params(name)
+}
+
+// Query returns a query parameter of the upgrade request.
+func (c *WSConn) Query(key string) string {
+	return c.conn.Query(key)
+}
+
+// ReadMessage waits for the next text or binary message.
+func (c *WSConn) ReadMessage() (messageType int, data []byte, err error) {
+	return c.conn.ReadMessage()
+}
+
+// ReadJSON decodes the next message into v.
+func (c *WSConn) ReadJSON(v interface{}) error {
+	_, data, err := c.conn.ReadMessage()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// Send queues a text message. A client that can't keep up with its send
+// buffer is disconnected.
+func (c *WSConn) Send(data []byte) error {
+	return c.enqueue(wsMessage{websocket.TextMessage, data})
+}
+
+// SendBinary queues a binary message.
+func (c *WSConn) SendBinary(data []byte) error {
+	return c.enqueue(wsMessage{websocket.BinaryMessage, data})
+}
+
+// SendJSON queues v encoded as JSON.
+func (c *WSConn) SendJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.Send(data)
+}
+
+func (c *WSConn) enqueue(message wsMessage) error {
+	select {
+	case <-c.closed:
+		return ErrWSClosed
+	default:
+	}
+	select {
+	case c.send <- message:
+		return nil
+	case <-c.closed:
+		return ErrWSClosed
+	default:
+		c.CloseWithReason(websocket.ClosePolicyViolation, "send buffer full")
+		return ErrWSClosed
+	}
+}
+
+// Join adds the connection to room.
+func (c *WSConn) Join(room string) {
+	c.hub.join(c, room)
+}
+
+// Leave removes the connection from room.
+func (c *WSConn) Leave(room string) {
+	c.hub.leave(c, room)
+}
+
+// Rooms lists the rooms the connection is in.
+func (c *WSConn) Rooms() []string {
+	return c.hub.roomsOf(c)
+}
+
+// Close closes the connection normally.
+func (c *WSConn) Close() {
+	c.CloseWithReason(websocket.CloseNormalClosure, "")
+}
+
+// CloseWithReason sends a close message with code and reason, then closes
+// the connection, making pending reads fail.
+func (c *WSConn) CloseWithReason(code int, reason string) {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		deadline := time.Now().Add(c.config.WriteTimeout)
+		c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), deadline)
+		// The hijacked connection is only closed once the handler returns,
+		// so fail its pending read instead.
+		c.conn.SetReadDeadline(time.Now())
+		c.conn.Close()
+	})
+}
+
+// writeLoop writes the queued messages and pings the client until the
+// connection is closed.
+func (c *WSConn) writeLoop() {
+	defer close(c.done)
+	ticker := time.NewTicker(c.config.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case message := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(c.config.WriteTimeout))
+			if err := c.conn.WriteMessage(message.messageType, message.data); err != nil {
+				c.CloseWithReason(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-ticker.C:
+			deadline := time.Now().Add(c.config.WriteTimeout)
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, deadline); err != nil {
+				c.CloseWithReason(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-c.closed:
+			return
+		}
+	}
+}
+
+// Hub tracks the open WebSocket connections and their rooms. Broadcasts can
+// come from any goroutine, such as queue job handlers.
+type Hub struct {
+	config WebSocketConfig
+	logger *logger.Logger
+
+	mu       sync.RWMutex
+	conns    map[*WSConn]map[string]bool
+	rooms    map[string]map[*WSConn]bool
+	users    map[string]int
+	closing  bool
+	finished sync.WaitGroup
+}
+
+func newHub(app *Application) *Hub {
+	return &Hub{
+		config: app.config.WebSocket.withDefaults(),
+		logger: app.logger,
+		conns:  make(map[*WSConn]map[string]bool),
+		rooms:  make(map[string]map[*WSConn]bool),
+		users:  make(map[string]int),
+	}
+}
+
+// Hub returns the hub of the application's WebSocket connections.
+func (app *Application) Hub() *Hub {
+	return app.hub
+}
+
+// Count returns the number of open connections.
+func (h *Hub) Count() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.conns)
+}
+
+// Room returns the connections in room.
+func (h *Hub) Room(room string) []*WSConn {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	conns := make([]*WSConn, 0, len(h.rooms[room]))
+	for conn := range h.rooms[room] {
+		conns = append(conns, conn)
+	}
+	return conns
+}
+
+// Broadcast sends a text message to every connection.
+func (h *Hub) Broadcast(data []byte) {
+	h.mu.RLock()
+	conns := make([]*WSConn, 0, len(h.conns))
+	for conn := range h.conns {
+		conns = append(conns, conn)
+	}
+	h.mu.RUnlock()
+
+	for _, conn := range conns {
+		conn.Send(data)
+	}
+}
+
+// BroadcastJSON sends v encoded as JSON to every connection.
+func (h *Hub) BroadcastJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	h.Broadcast(data)
+	return nil
+}
+
+// BroadcastTo sends a text message to the connections in room.
+func (h *Hub) BroadcastTo(room string, data []byte) {
+	for _, conn := range h.Room(room) {
+		conn.Send(data)
+	}
+}
+
+// BroadcastJSONTo sends v encoded as JSON to the connections in room.
+func (h *Hub) BroadcastJSONTo(room string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	h.BroadcastTo(room, data)
+	return nil
+}
+
+// admit reports why a new connection of userID would be refused, as an
+// error with the HTTP status to answer the upgrade request with.
+func (h *Hub) admit(userID string) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	switch {
+	case h.closing:
+		return NewAppError("server is shutting down", http.StatusServiceUnavailable)
+	case h.config.MaxConnections > 0 && len(h.conns) >= h.config.MaxConnections:
+		return NewAppError("too many websocket connections", http.StatusServiceUnavailable)
+	case userID != "" && h.config.MaxConnectionsPerUser > 0 && h.users[userID] >= h.config.MaxConnectionsPerUser:
+		return NewAppError("too many websocket connections for this user", http.StatusTooManyRequests)
+	}
+	return nil
+}
+
+// register tracks conn, failing if a limit was reached since admit.
+func (h *Hub) register(conn *WSConn) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	switch {
+	case h.closing:
+		return errors.New("server is shutting down")
+	case h.config.MaxConnections > 0 && len(h.conns) >= h.config.MaxConnections,
+		conn.userID != "" && h.config.MaxConnectionsPerUser > 0 && h.users[conn.userID] >= h.config.MaxConnectionsPerUser:
+		return errors.New("too many connections")
+	}
+	h.conns[conn] = make(map[string]bool)
+	if conn.userID != "" {
+		h.users[conn.userID]++
+	}
+	h.finished.Add(1)
+	return nil
+}
+
+func (h *Hub) unregister(conn *WSConn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	rooms, ok := h.conns[conn]
+	if !ok {
+		return
+	}
+	for room := range rooms {
+		h.removeFromRoom(conn, room)
+	}
+	delete(h.conns, conn)
+	if conn.userID != "" {
+		if h.users[conn.userID]--; h.users[conn.userID] == 0 {
+			delete(h.users, conn.userID)
+		}
+	}
+	h.finished.Done()
+}
+
+func (h *Hub) join(conn *WSConn, room string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	rooms, ok := h.conns[conn]
+	if !ok {
+		return
+	}
+	rooms[room] = true
+	if h.rooms[room] == nil {
+		h.rooms[room] = make(map[*WSConn]bool)
+	}
+	h.rooms[room][conn] = true
+}
+
+func (h *Hub) leave(conn *WSConn, room string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if rooms, ok := h.conns[conn]; ok {
+		delete(rooms, room)
+		h.removeFromRoom(conn, room)
+	}
+}
+
+// removeFromRoom drops conn from room, deleting empty rooms. The caller
+// must hold h.mu.
+func (h *Hub) removeFromRoom(conn *WSConn, room string) {
+	delete(h.rooms[room], conn)
+	if len(h.rooms[room]) == 0 {
+		delete(h.rooms, room)
+	}
+}
+
+func (h *Hub) roomsOf(conn *WSConn) []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	var rooms []string
+	for room := range h.conns[conn] {
+		rooms = append(rooms, room)
+	}
+	return rooms
+}
+
+// Shutdown refuses new connections, closes the open ones with "going away"
+// and waits for their handlers to return or ctx to end.
+func (h *Hub) Shutdown(ctx context.Context) error {
+	h.mu.Lock()
+	h.closing = true
+	conns := make([]*WSConn, 0, len(h.conns))
+	for conn := range h.conns {
+		conns = append(conns, conn)
+	}
+	h.mu.Unlock()
+
+	for _, conn := range conns {
+		conn.CloseWithReason(websocket.CloseGoingAway, "server shutting down")
+	}
+
+	finished := make(chan struct{})
+	go func() {
+		h.finished.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// isWebSocketHandler reports whether method is named HandleWs<Name>.
+func isWebSocketHandler(method reflect.Method) bool {
+	name := strings.TrimPrefix(method.Name, "HandleWs")
+	return name != method.Name && (name == "" || (name[0] >= 'A' && name[0] <= 'Z'))
+}
+
+var wsConnType = reflect.TypeOf((*WSConn)(nil))
+
+// wsReceiverKey holds the controller with its request scoped dependencies
+// for the connection.
+const wsReceiverKey = "forge.ws.receiver"
+
+// checkWebSocketHandler checks a HandleWs* method takes a *WSConn and
+// returns nothing or an error.
+func checkWebSocketHandler(method reflect.Method) error {
+	t := method.Type
+	if t.NumIn() != 2 || t.In(1) != wsConnType || t.NumOut() > 1 || (t.NumOut() == 1 && t.Out(0) != errorType) {
+		return fmt.Errorf("%s must be func(*forge.WSConn) or func(*forge.WSConn) error", method.Name)
+	}
+	return nil
+}
+
+// createWSHandler upgrades requests to WebSocket connections served by
+// method. Middleware runs on the upgrade request, so authentication applies
+// before the connection is accepted.
+func (app *Application) createWSHandler(method reflect.Method, controllerValue reflect.Value, requestFields []injectField, middleware []MiddlewareFunc) fiber.Handler {
+	hub := app.hub
+	var origins []string
+	if allowed := app.config.CORS.AllowOrigins; allowed != "" {
+		for _, origin := range strings.Split(allowed, ",") {
+			origins = append(origins, strings.TrimSpace(origin))
+		}
+	}
+
+	upgrade := websocket.New(func(c *websocket.Conn) {
+		conn := &WSConn{
+			conn:   c,
+			hub:    hub,
+			id:     wsConnIDs.Add(1),
+			config: hub.config,
+			send:   make(chan wsMessage, hub.config.SendBuffer),
+			closed: make(chan struct{}),
+			done:   make(chan struct{}),
+		}
+		if id := c.Locals("user_id"); id != nil {
+			conn.userID = fmt.Sprint(id)
+		}
+		if err := hub.register(conn); err != nil {
+			conn.CloseWithReason(websocket.CloseTryAgainLater, err.Error())
+			return
+		}
+		defer hub.unregister(conn)
+
+		c.SetReadLimit(hub.config.MaxMessageSize)
+		c.SetReadDeadline(time.Now().Add(hub.config.PongTimeout))
+		c.SetPongHandler(func(string) error {
+			select {
+			case <-conn.closed:
+				return ErrWSClosed
+			default:
+				return c.SetReadDeadline(time.Now().Add(hub.config.PongTimeout))
+			}
+		})
+		go conn.writeLoop()
+
+		receiver, ok := c.Locals(wsReceiverKey).(reflect.Value)
+		if !ok {
+			receiver = controllerValue
+		}
+		result := method.Func.Call([]reflect.Value{receiver, reflect.ValueOf(conn)})
+		if len(result) == 1 {
+			if err, ok := result[0].Interface().(error); ok && err != nil {
+				hub.logger.Error("WebSocket handler %s failed: %v", method.Name, err)
+				conn.CloseWithReason(websocket.CloseInternalServerErr, "")
+			}
+		}
+		conn.Close()
+		<-conn.done
+	}, websocket.Config{Origins: origins})
+
+	handler := func(ctx *Context) error {
+		if !websocket.IsWebSocketUpgrade(ctx.Ctx) {
+			return fiber.ErrUpgradeRequired
+		}
+		userID := ""
+		if id := ctx.Locals("user_id"); id != nil {
+			userID = fmt.Sprint(id)
+		}
+		if err := hub.admit(userID); err != nil {
+			return err
+		}
+		// Request scoped dependencies are resolved from the upgrade request.
+		if len(requestFields) > 0 {
+			scoped, err := app.withRequestFields(controllerValue, requestFields, ctx)
+			if err != nil {
+				return err
+			}
+			ctx.Locals(wsReceiverKey, scoped)
+		}
+		return upgrade(ctx.Ctx)
+	}
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return func(c *fiber.Ctx) error {
+		return handler(app.context(c))
+	}
+}
@@ -0,0 +1,226 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ChatController struct {
+	Controller
+}
+
+func (c *ChatController) HandleWsRoomByName(conn *WSConn) {
+	conn.Join(conn.Param("name"))
+	conn.SendJSON(map[string]interface{}{"user": conn.UserID(), "name": conn.Claims()["name"], "rooms": conn.Rooms()})
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		c.App().Hub().BroadcastTo(conn.Param("name"), message)
+	}
+}
+
+func (c *ChatController) HandleWsFail(conn *WSConn) error {
+	return errors.New("failed")
+}
+
+type BadWebSocketController struct {
+	Controller
+}
+
+func (c *BadWebSocketController) HandleWsEcho(ctx *Context) error { return nil }
+
+// fakeAuth stands in for middleware.RequireAuth, taking the user from the
+// X-User header.
+func fakeAuth(next HandlerFunc) HandlerFunc {
+	return func(ctx *Context) error {
+		user := ctx.Get("X-User")
+		if user == "" {
+			return ErrUnauthorized
+		}
+		ctx.Locals("user_id", user)
+		ctx.Locals("claims", map[string]interface{}{"sub": user, "name": "user " + user})
+		return next(ctx)
+	}
+}
+
+// serveChat serves a ChatController behind fakeAuth, returning its
+// ws:// base URL.
+func serveChat(t *testing.T, config *Config) (*Application, string) {
+	app := newTestApp(t, config)
+	controller := &ChatController{}
+	controller.Use(fakeAuth)
+	require.NoError(t, app.RegisterController(controller))
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go app.Serve(ln)
+	t.Cleanup(func() { app.ShutdownWithContext(context.Background()) })
+	return app, "ws://" + ln.Addr().String()
+}
+
+func dialChat(t *testing.T, url, user string) (*websocket.Conn, *http.Response, error) {
+	header := http.Header{"Origin": {"http://localhost"}}
+	if user != "" {
+		header.Set("X-User", user)
+	}
+	var conn *websocket.Conn
+	var resp *http.Response
+	var err error
+	require.Eventually(t, func() bool {
+		conn, resp, err = websocket.DefaultDialer.Dial(url, header)
+		return resp != nil || err == nil
+	}, 5*time.Second, 20*time.Millisecond, "server did not come up")
+	if conn != nil {
+		t.Cleanup(func() { conn.Close() })
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	}
+	return conn, resp, err
+}
+
+func TestWebSocketRoute(t *testing.T) {
+	app := newTestApp(t, &Config{})
+	require.NoError(t, app.RegisterController(&ChatController{}))
+
+	route := findRoute(app.Routes(), http.MethodGet, "/chat/room/:name")
+	require.NotNil(t, route)
+	assert.Equal(t, "HandleWsRoomByName", route.Handler)
+	assert.NotNil(t, findRoute(app.Routes(), http.MethodGet, "/chat/fail"))
+
+	spec, err := app.GenerateOpenAPI()
+	require.NoError(t, err)
+	assert.NotContains(t, spec.Paths, "/chat/room/{name}", "WebSocket routes are not HTTP operations")
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/chat/room/lobby", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUpgradeRequired, resp.StatusCode)
+
+	err = newTestApp(t, &Config{}).RegisterController(&BadWebSocketController{})
+	assert.ErrorContains(t, err, "BadWebSocketController.HandleWsEcho must be func(*forge.WSConn)")
+}
+
+func TestWebSocketRoomsAndBroadcast(t *testing.T) {
+	app, url := serveChat(t, &Config{})
+
+	_, resp, err := dialChat(t, url+"/chat/room/lobby", "")
+	require.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, "middleware runs before the upgrade")
+
+	alice, _, err := dialChat(t, url+"/chat/room/lobby", "1")
+	require.NoError(t, err)
+	bob, _, err := dialChat(t, url+"/chat/room/lobby", "2")
+	require.NoError(t, err)
+	carol, _, err := dialChat(t, url+"/chat/room/kitchen", "3")
+	require.NoError(t, err)
+
+	var welcome map[string]interface{}
+	require.NoError(t, alice.ReadJSON(&welcome))
+	assert.Equal(t, map[string]interface{}{"user": "1", "name": "user 1", "rooms": []interface{}{"lobby"}}, welcome)
+	require.NoError(t, bob.ReadJSON(&welcome))
+	require.NoError(t, carol.ReadJSON(&welcome))
+	assert.Equal(t, 3, app.Hub().Count())
+	assert.Len(t, app.Hub().Room("lobby"), 2)
+
+	require.NoError(t, alice.WriteMessage(websocket.TextMessage, []byte("hi lobby")))
+	for _, conn := range []*websocket.Conn{alice, bob} {
+		_, message, err := conn.ReadMessage()
+		require.NoError(t, err)
+		assert.Equal(t, "hi lobby", string(message))
+	}
+
+	// Broadcasts can come from outside a handler, such as a queue job.
+	require.NoError(t, app.Hub().BroadcastJSON(map[string]string{"event": "deploy"}))
+	for _, conn := range []*websocket.Conn{alice, bob, carol} {
+		_, message, err := conn.ReadMessage()
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"event":"deploy"}`, string(message))
+	}
+
+	bob.Close()
+	assert.Eventually(t, func() bool { return len(app.Hub().Room("lobby")) == 1 }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestWebSocketHandlerError(t *testing.T) {
+	_, url := serveChat(t, &Config{})
+
+	conn, _, err := dialChat(t, url+"/chat/fail", "1")
+	require.NoError(t, err)
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseInternalServerErr), err)
+}
+
+func TestWebSocketConnectionLimits(t *testing.T) {
+	_, url := serveChat(t, &Config{WebSocket: WebSocketConfig{MaxConnections: 2, MaxConnectionsPerUser: 1}})
+
+	first, _, err := dialChat(t, url+"/chat/room/lobby", "1")
+	require.NoError(t, err)
+	var welcome map[string]interface{}
+	require.NoError(t, first.ReadJSON(&welcome))
+
+	_, resp, err := dialChat(t, url+"/chat/room/lobby", "1")
+	require.Error(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+
+	second, _, err := dialChat(t, url+"/chat/room/lobby", "2")
+	require.NoError(t, err)
+	require.NoError(t, second.ReadJSON(&welcome))
+
+	_, resp, err = dialChat(t, url+"/chat/room/lobby", "3")
+	require.Error(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
+
+func TestWebSocketPing(t *testing.T) {
+	_, url := serveChat(t, &Config{WebSocket: WebSocketConfig{PingInterval: 20 * time.Millisecond}})
+
+	conn, _, err := dialChat(t, url+"/chat/room/lobby", "1")
+	require.NoError(t, err)
+	pinged := make(chan struct{}, 1)
+	conn.SetPingHandler(func(string) error {
+		select {
+		case pinged <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-pinged:
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not ping")
+	}
+}
+
+func TestWebSocketShutdown(t *testing.T) {
+	app, url := serveChat(t, &Config{})
+
+	conn, _, err := dialChat(t, url+"/chat/room/lobby", "1")
+	require.NoError(t, err)
+	var welcome map[string]interface{}
+	require.NoError(t, conn.ReadJSON(&welcome))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, app.ShutdownWithContext(ctx))
+	assert.Zero(t, app.Hub().Count())
+
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), err)
+}
@@ -36,7 +36,9 @@ func main() {
 		log.Fatalf("Failed to create application: %v", err)
 	}
 
-	app.RegisterController(&HelloController{})
+	if err := app.RegisterController(&HelloController{}); err != nil {
+		log.Fatalf("Failed to register controller: %v", err)
+	}
 
 	fmt.Println("Server starting on http://localhost:3000")
 	if err := app.Start(); err != nil {
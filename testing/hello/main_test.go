@@ -24,7 +24,7 @@ func TestHelloEndpoint(t *testing.T) {
 	assert.NoError(t, err)
 
 	
-	app.RegisterController(&HelloController{})
+	assert.NoError(t, app.RegisterController(&HelloController{}))
 
 	
 	req := httptest.NewRequest("GET", "/hello", nil)
@@ -0,0 +1,20 @@
+// Code generated by forge generate:routes. DO NOT EDIT.
+
+package main
+
+import (
+	"github.com/BisiOlaYemi/forge/pkg/forge"
+)
+
+// Routes returns the routes annotated on HelloController.
+func (c *HelloController) Routes() []forge.RouteMetadata {
+	return []forge.RouteMetadata{
+		{
+			Handler:     "HandleGetHello",
+			Method:      "GET",
+			Path:        "/hello",
+			Description: "Get a hello message",
+			Response:    forge.H{},
+		},
+	}
+}